@@ -20,8 +20,12 @@ import (
 
 // cliArgs аргументы утилиты
 type cliArgs struct {
-	Version  VersionCommand  `cmd:"" help:"Print version and exit."`
-	Generate GenerateCommand `cmd:"" help:"Generate conversions."`
+	Version     VersionCommand     `cmd:"" help:"Print version and exit."`
+	Generate    GenerateCommand    `cmd:"" help:"Generate conversions."`
+	GenerateAll GenerateAllCommand `cmd:"" help:"Generate conversions for all pairs listed in the manifest."`
+	Check       CheckCommand       `cmd:"" help:"Check generated conversions are up to date."`
+	CheckAll    CheckAllCommand    `cmd:"" help:"Check generated conversions of all pairs listed in the manifest are up to date."`
+	Explain     ExplainCommand     `cmd:"" help:"Show how fields of structures are matched."`
 
 	InstallCompletions kongplete.InstallCompletions `cmd:"" help:"Install shell completions."`
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"awesome-converter/internal/generator"
+	"github.com/sirkon/message"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// CheckCommand команда проверки актуальности сгенерированных преобразований.
+type CheckCommand struct {
+	generateArgs
+}
+
+// Run запуск проверки
+func (c *CheckCommand) Run(rctx *RunContext) error {
+	prj, err := c.generate(generator.WithoutManualStubs())
+	if err != nil {
+		return err
+	}
+
+	return checkGenerated(prj)
+}
+
+// CheckAllCommand команда проверки актуальности преобразований всех пар структур из манифеста.
+type CheckAllCommand struct {
+	manifestArgs
+}
+
+// Run запуск проверки
+func (c *CheckAllCommand) Run(rctx *RunContext) error {
+	prj, err := c.generate(generator.WithoutManualStubs())
+	if err != nil {
+		return err
+	}
+
+	return checkGenerated(prj)
+}
+
+// checkGenerated сравнение сгенерированного кода с файлами проекта, отличия выводятся в виде diff
+func checkGenerated(prj *matiss.Project) error {
+	files, err := renderAside(prj)
+	if err != nil {
+		return errors.Wrap(err, "render generated code aside")
+	}
+
+	var stale []string
+	for _, f := range files {
+		if !f.changed() {
+			continue
+		}
+
+		diff, err := f.diff()
+		if err != nil {
+			return errors.Wrap(err, "compute difference for "+f.path)
+		}
+
+		fmt.Print(diff)
+		stale = append(stale, f.path)
+	}
+
+	if len(stale) > 0 {
+		return errors.Newf("generated conversions are stale: %s", strings.Join(stale, ", "))
+	}
+
+	message.Info("generated conversions are up to date")
+	return nil
+}
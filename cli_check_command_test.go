@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdirTestModule создание временного модуля с данными файлами и переход в его корень на время теста
+func chdirTestModule(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain is not available")
+	}
+
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/fresh\n\ngo 1.18\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+const testDomainUser = `package domain
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserDTO struct {
+	ID   string
+	Name string
+}
+`
+
+func testGenerateArgs(t *testing.T) generateArgs {
+	t.Helper()
+
+	args := generateArgs{
+		Primary: structPath{
+			needLocal: true,
+		},
+	}
+	if err := args.Primary.UnmarshalText([]byte("./domain:User")); err != nil {
+		t.Fatal(err)
+	}
+	if err := args.Secondary.UnmarshalText([]byte("./domain:UserDTO")); err != nil {
+		t.Fatal(err)
+	}
+
+	return args
+}
+
+// makeStale изменение сгенерированного файла так, чтобы он перестал соответствовать структурам
+func makeStale(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join("domain", "user_convgen.go")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, "\n// edited\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCommand_Run(t *testing.T) {
+	chdirTestModule(t, map[string]string{
+		"domain/user.go": testDomainUser,
+	})
+	args := testGenerateArgs(t)
+
+	check := &CheckCommand{generateArgs: args}
+	if err := check.Run(&RunContext{}); err == nil {
+		t.Fatal("check must fail when conversions were not generated yet")
+	}
+
+	gen := &GenerateCommand{generateArgs: args}
+	if err := gen.Run(&RunContext{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := check.Run(&RunContext{}); err != nil {
+		t.Fatalf("check must pass for up to date conversions: %v", err)
+	}
+
+	makeStale(t)
+	err := check.Run(&RunContext{})
+	if err == nil {
+		t.Fatal("check must fail for stale conversions")
+	}
+	if !strings.Contains(err.Error(), filepath.Join("domain", "user_convgen.go")) {
+		t.Errorf("stale file must be reported, got: %v", err)
+	}
+}
+
+func TestCheckAllCommand_Run(t *testing.T) {
+	chdirTestModule(t, map[string]string{
+		"domain/user.go": testDomainUser,
+		".awesome-converter.yaml": `
+conversions:
+  - primary: ./domain:User
+    secondary: ./domain:UserDTO
+`,
+	})
+	args := manifestArgs{
+		Manifest: ".awesome-converter.yaml",
+	}
+
+	gen := &GenerateAllCommand{manifestArgs: args}
+	if err := gen.Run(&RunContext{}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := &CheckAllCommand{manifestArgs: args}
+	if err := check.Run(&RunContext{}); err != nil {
+		t.Fatalf("check must pass for up to date conversions: %v", err)
+	}
+
+	makeStale(t)
+	if err := check.Run(&RunContext{}); err == nil {
+		t.Fatal("check must fail for stale conversions")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+)
+
+// ExplainCommand команда показа сопоставления полей структур.
+type ExplainCommand struct {
+	generateArgs
+
+	Format string `help:"Output format." enum:"text,json" default:"text"`
+}
+
+// Run запуск команды
+func (c *ExplainCommand) Run(rctx *RunContext) error {
+	g, err := c.generator()
+	if err != nil {
+		return err
+	}
+
+	if c.Format == "text" {
+		g.Report()
+		return nil
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g.Explain()); err != nil {
+		return errors.Wrap(err, "encode fields matching")
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"awesome-converter/internal/generator"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// GenerateAllCommand команда генерации преобразований для всех пар структур из манифеста.
+type GenerateAllCommand struct {
+	manifestArgs
+}
+
+// manifestArgs аргументы генерации преобразований пар структур из манифеста
+type manifestArgs struct {
+	Manifest string `short:"f" help:"Path to the conversions manifest." default:".awesome-converter.yaml"`
+}
+
+// Run запуск генерации
+func (c *GenerateAllCommand) Run(rctx *RunContext) error {
+	prj, err := c.generate()
+	if err != nil {
+		return err
+	}
+
+	dir := matiss.Directory(".")
+	if err := prj.Render(dir); err != nil {
+		return errors.Wrap(err, "render generated source code")
+	}
+
+	return nil
+}
+
+// generate генерация кода преобразований всех пар структур из манифеста без отрисовки, opts дополняют опции
+// заданные манифестом
+func (c *manifestArgs) generate(opts ...generator.Option) (*matiss.Project, error) {
+	m, err := loadManifest(c.Manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "load manifest")
+	}
+
+	modPath, err := currentModulePath()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve current module information")
+	}
+
+	pairs, err := m.pairs(modPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "process manifest")
+	}
+	for i := range pairs {
+		pairs[i].Options = append(pairs[i].Options, opts...)
+	}
+
+	gens, err := generator.NewBatch(pairs)
+	if err != nil {
+		return nil, errors.Wrap(err, "setup generators")
+	}
+
+	prj, err := matiss.UpdateProject()
+	if err != nil {
+		return nil, errors.Wrap(err, "setup matiss for the current project")
+	}
+
+	for _, g := range gens {
+		if err := g.Generate(prj); err != nil {
+			return nil, errors.Wrap(err, "generate source code")
+		}
+	}
+
+	return prj, nil
+}
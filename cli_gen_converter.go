@@ -1,11 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"awesome-converter/internal/generator"
 	"github.com/sirkon/jsonexec"
+	"github.com/sirkon/message"
 
 	"gitlab.stageoffice.ru/UCS-COMMON/errors"
 	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
@@ -13,47 +15,203 @@ import (
 
 // GenerateCommand команда генерации преобразований.
 type GenerateCommand struct {
-	Primary       structPath `arg:"" help:"Primary structure to generate conversions in its package. Must look like <rel-path>:<name>." predictor:"local-struct-path"`
-	Secondary     structPath `arg:"" help:"Secondary structure to generate conversions to and from the primary one. Must look like <pkg-path>:<name>." predictor:"free-struct-path"`
-	PrimaryMethod string     `short:"m" help:"MethodPrimary name for the primary -> secondary conversion. Free function will be generated instead if not set."`
+	generateArgs
+
+	DryRun bool `help:"Print difference between generated and existing code instead of writing files." xor:"output"`
+	Stdout bool `help:"Print generated code instead of writing files." xor:"output"`
+}
+
+// generateArgs аргументы генерации преобразований пары структур
+type generateArgs struct {
+	Primary        structPath        `arg:"" help:"Primary structure to generate conversions in its package. Must look like <rel-path>:<name>." predictor:"local-struct-path"`
+	Secondary      structPath        `arg:"" help:"Secondary structure to generate conversions to and from the primary one. Must look like <pkg-path>:<name>." predictor:"free-struct-path"`
+	PrimaryMethod  string            `short:"m" help:"MethodPrimary name for the primary -> secondary conversion. Free function will be generated instead if not set."`
+	Map            map[string]string `help:"Manual fields matching in form of PrimField=SecField. Can be repeated." placeholder:"PRIM=SEC"`
+	Strict         bool              `help:"Fail instead of calling user defined conversion when there are unmatched fields."`
+	StrictTo       bool              `help:"Same as --strict for the primary -> secondary conversion only."`
+	StrictFrom     bool              `help:"Same as --strict for the secondary -> primary conversion only."`
+	FieldHooks     bool              `help:"Call user defined conversion for each unmatched field instead of one for the whole structure."`
+	FromFunc       string            `help:"Function name for the secondary -> primary conversion instead of <Sec>To<Prim>." xor:"from"`
+	FromMethod     string            `help:"Method name of the secondary structure for the secondary -> primary conversion. The secondary must be in the package of the primary." xor:"from"`
+	NoTo           bool              `help:"Do not generate the primary -> secondary conversion."`
+	NoFrom         bool              `help:"Do not generate the secondary -> primary conversion."`
+	EnumFallback   string            `help:"Conversion of enum values having no match: error, default, default:NAME or raw." default:"error"`
+	EnumFallbacks  map[string]string `help:"Same as --enum-fallback for conversions into the given enum type. Can be repeated." placeholder:"ENUM=FALLBACK"`
+	EnumMapTo      map[string]string `help:"Manual matching of enum constants for the primary -> secondary conversion. Several constants can be matched to one. Can be repeated." placeholder:"PRIM=SEC"`
+	EnumMapFrom    map[string]string `help:"Manual matching of enum constants for the secondary -> primary conversion. Several constants can be matched to one. Can be repeated." placeholder:"SEC=PRIM"`
+	CheckNarrowing bool              `help:"Check numeric conversions that can lose data and fail on out of range values, lost fractions and precision, and NaNs converted to integers."`
+	CollectErrors  bool              `help:"Convert all fields and return all their errors together instead of stopping at the first one."`
+	Errors         string            `help:"Error library of the generated code: ucs (UCS-COMMON errors), pkg (github.com/pkg/errors) or std (errors and fmt.Errorf)." default:"ucs"`
 }
 
 // Run запуск генерации
 func (c *GenerateCommand) Run(rctx *RunContext) error {
-	var listInfo struct {
-		Dir  string
-		Path string
+	prj, err := c.generate()
+	if err != nil {
+		return err
 	}
-	if err := jsonexec.Run(&listInfo, "go", "list", "-m", "--json"); err != nil {
-		return errors.Wrap(err, "retrieve current module information")
+
+	if c.DryRun || c.Stdout {
+		// заготовки функций ручной конвертации выводятся вместе с остальным кодом
+		return c.print(prj)
+	}
+
+	dir := matiss.Directory(".")
+	if err := prj.Render(dir); err != nil {
+		return errors.Wrap(err, "render generated source code")
+	}
+
+	return nil
+}
+
+// print вывод сгенерированного кода либо его отличий от текущего без изменения файлов проекта
+func (c *GenerateCommand) print(prj *matiss.Project) error {
+	files, err := renderAside(prj)
+	if err != nil {
+		return errors.Wrap(err, "render generated code aside")
+	}
+
+	for _, f := range files {
+		if c.Stdout {
+			fmt.Printf("// %s\n", f.path)
+			fmt.Print(string(f.content))
+			continue
+		}
+
+		if !f.changed() {
+			message.Infof("%s is up to date", f.path)
+			continue
+		}
+
+		diff, err := f.diff()
+		if err != nil {
+			return errors.Wrap(err, "compute difference for "+f.path)
+		}
+
+		fmt.Print(diff)
+	}
+
+	return nil
+}
+
+// generate генерация кода преобразований без отрисовки, opts дополняют опции заданные аргументами
+func (c *generateArgs) generate(opts ...generator.Option) (*matiss.Project, error) {
+	g, err := c.generator(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	prj, err := matiss.UpdateProject()
+	if err != nil {
+		return nil, errors.Wrap(err, "setup matiss for the current project")
+	}
+
+	if err := g.Generate(prj); err != nil {
+		return nil, errors.Wrap(err, "generate source code")
+	}
+
+	return prj, nil
+}
+
+// generator создание генератора для заданной пары структур, extra дополняют опции заданные аргументами
+func (c *generateArgs) generator(extra ...generator.Option) (*generator.Generator, error) {
+	modPath, err := currentModulePath()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve current module information")
+	}
+
+	opts := []generator.Option{
+		generator.WithManualMatches(c.Map),
+		generator.WithStrict(c.Strict || c.StrictTo, c.Strict || c.StrictFrom),
+	}
+	if c.FieldHooks {
+		opts = append(opts, generator.WithFieldHooks())
+	}
+	if c.FromFunc != "" {
+		opts = append(opts, generator.WithFromFunc(c.FromFunc))
+	}
+	if c.FromMethod != "" {
+		opts = append(opts, generator.WithFromMethod(c.FromMethod))
+	}
+	if c.NoTo {
+		opts = append(opts, generator.WithoutTo())
+	}
+	if c.NoFrom {
+		opts = append(opts, generator.WithoutFrom())
+	}
+	if c.CheckNarrowing {
+		opts = append(opts, generator.WithNarrowingChecks())
+	}
+	if c.CollectErrors {
+		opts = append(opts, generator.WithCollectErrors())
+	}
+	if len(c.EnumMapTo) > 0 || len(c.EnumMapFrom) > 0 {
+		opts = append(opts, generator.WithEnumMapping(c.EnumMapTo, c.EnumMapFrom))
+	}
+	fallbacks, err := enumFallbackOptions(c.EnumFallback, c.EnumFallbacks)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, fallbacks...)
+	if c.Errors != "" {
+		backend, err := generator.ParseErrorsBackend(c.Errors)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, generator.WithErrorsBackend(backend))
 	}
+	opts = append(opts, extra...)
 
 	g, err := generator.New(
-		undottedPrefix(c.Primary.pkgPath, listInfo.Path),
+		undottedPrefix(c.Primary.pkgPath, modPath),
 		c.Primary.name,
-		undottedPrefix(c.Secondary.pkgPath, listInfo.Path),
+		undottedPrefix(c.Secondary.pkgPath, modPath),
 		c.Secondary.name,
 		c.PrimaryMethod,
+		opts...,
 	)
 	if err != nil {
-		return errors.Wrap(err, "setup generator")
+		return nil, errors.Wrap(err, "setup generator")
 	}
 
-	prj, err := matiss.UpdateProject()
-	if err != nil {
-		return errors.Wrap(err, "setup matiss for the current project")
+	return g, nil
+}
+
+// enumFallbackOptions опции поведения конвертации перечислений для значений не имеющих пары
+func enumFallbackOptions(fallback string, fallbacks map[string]string) ([]generator.Option, error) {
+	var res []generator.Option
+	if fallback != "" {
+		f, err := generator.ParseEnumFallback(fallback)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse enum fallback")
+		}
+
+		res = append(res, generator.WithEnumFallback(f))
 	}
 
-	if err := g.Generate(prj); err != nil {
-		return errors.Wrap(err, "generate source code")
+	for enum, fallback := range fallbacks {
+		f, err := generator.ParseEnumFallback(fallback)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse enum fallback for "+enum)
+		}
+
+		res = append(res, generator.WithEnumFallbackFor(enum, f))
 	}
 
-	dir := matiss.Directory(".")
-	if err := prj.Render(dir); err != nil {
-		return errors.Wrap(err, "render generated source code")
+	return res, nil
+}
+
+// currentModulePath получение пути текущего модуля
+func currentModulePath() (string, error) {
+	var listInfo struct {
+		Dir  string
+		Path string
+	}
+	if err := jsonexec.Run(&listInfo, "go", "list", "-m", "--json"); err != nil {
+		return "", err
 	}
 
-	return nil
+	return listInfo.Path, nil
 }
 
 func undottedPrefix(pkg, modPkg string) string {
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureStdout вывод в stdout во время выполнения f
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	ferr := f()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return <-output, ferr
+}
+
+func TestGenerateCommand_RunDryRun(t *testing.T) {
+	chdirTestModule(t, map[string]string{
+		"domain/user.go": `package domain
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserDTO struct {
+	ID   string
+	Name string
+	Nick string
+}
+`,
+	})
+
+	gen := &GenerateCommand{
+		generateArgs: testGenerateArgs(t),
+		DryRun:       true,
+	}
+	output, err := captureStdout(t, func() error {
+		return gen.Run(&RunContext{})
+	})
+	if err != nil {
+		t.Fatalf("dry run must not fail on missing user defined conversions: %v", err)
+	}
+
+	for _, want := range []string{
+		"+++ b/" + filepath.Join("domain", "user_convgen.go"),
+		"+++ b/" + filepath.Join("domain", "user_convmanual.go"),
+		"func manualUserDTOToUser(",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dry run output must contain %q, got:\n%s", want, output)
+		}
+	}
+
+	entries, err := os.ReadDir("domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dry run must not write files, got %d files in domain", len(entries))
+	}
+}
+
+func TestGenerateCommand_RunExistingStubs(t *testing.T) {
+	const user = `package domain
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserDTO struct {
+	ID   string
+	Name string
+	Nick string
+}
+`
+	chdirTestModule(t, map[string]string{
+		"domain/user.go": user,
+	})
+	gen := &GenerateCommand{generateArgs: testGenerateArgs(t)}
+
+	if err := gen.Run(&RunContext{}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := os.ReadFile(filepath.Join("domain", "user_convmanual.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(first), "func manualUserDTOToUser(") {
+		t.Fatalf("stub of secondary → primary conversion expected, got:\n%s", first)
+	}
+
+	// второй запуск с новым несопоставленным полем primary-структуры
+	updated := strings.Replace(user, "\tName string\n}\n\ntype UserDTO", "\tName  string\n\tExtra string\n}\n\ntype UserDTO", 1)
+	if err := os.WriteFile(filepath.Join("domain", "user.go"), []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Run(&RunContext{}); err != nil {
+		t.Fatalf("second run must not fail on existing stubs file: %v", err)
+	}
+
+	current, err := os.ReadFile(filepath.Join("domain", "user_convmanual.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != string(first) {
+		t.Errorf("existing stubs file must be kept as is, got:\n%s", current)
+	}
+
+	second, err := os.ReadFile(filepath.Join("domain", "user_convmanual2.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(second), "func manualUserToUserDTO(") {
+		t.Errorf("stub of primary → secondary conversion expected, got:\n%s", second)
+	}
+	if strings.Contains(string(second), "func manualUserDTOToUser(") {
+		t.Errorf("existing stub must not be generated again, got:\n%s", second)
+	}
+}
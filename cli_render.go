@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"awesome-converter/internal/app"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// renderedFile сгенерированный файл и текущее содержимое файла проекта по тому же пути
+type renderedFile struct {
+	// path путь файла относительно корня проекта
+	path string
+	// content сгенерированное содержимое
+	content []byte
+	// current текущее содержимое, nil если файла нет
+	current []byte
+}
+
+// changed сгенерированное содержимое отличается от текущего
+func (f *renderedFile) changed() bool {
+	return f.current == nil || !bytes.Equal(f.current, f.content)
+}
+
+// diff унифицированный diff между текущим и сгенерированным содержимым
+func (f *renderedFile) diff() (string, error) {
+	from := "a/" + f.path
+	if f.current == nil {
+		from = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(f.current)),
+		B:        difflib.SplitLines(string(f.content)),
+		FromFile: from,
+		ToFile:   "b/" + f.path,
+		Context:  3,
+	})
+}
+
+// renderAside отрисовка сгенерированного кода во временный каталог без изменения файлов проекта
+func renderAside(prj *matiss.Project) ([]renderedFile, error) {
+	tmp, err := os.MkdirTemp("", app.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "create temporary directory")
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := prj.Render(matiss.Directory(tmp)); err != nil {
+		return nil, errors.Wrap(err, "render generated source code")
+	}
+
+	var res []renderedFile
+	err = filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return errors.Wrap(err, "compute relative path of "+path)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "read rendered file "+rel)
+		}
+
+		current, err := os.ReadFile(rel)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "read project file "+rel)
+		}
+
+		res = append(res, renderedFile{
+			path:    rel,
+			content: content,
+			current: current,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "look for rendered files")
+	}
+
+	return res, nil
+}
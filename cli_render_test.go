@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_renderedFile_diff(t *testing.T) {
+	tests := []struct {
+		name string
+		file renderedFile
+		want []string
+	}{
+		{
+			name: "new-file",
+			file: renderedFile{
+				path:    "domain/user_convmanual.go",
+				content: []byte("package domain\n"),
+			},
+			want: []string{
+				"--- /dev/null\n",
+				"+++ b/domain/user_convmanual.go\n",
+				"+package domain\n",
+			},
+		},
+		{
+			name: "changed-file",
+			file: renderedFile{
+				path:    "domain/user_convgen.go",
+				content: []byte("package domain\n\nfunc New() {}\n"),
+				current: []byte("package domain\n\nfunc Old() {}\n"),
+			},
+			want: []string{
+				"--- a/domain/user_convgen.go\n",
+				"+++ b/domain/user_convgen.go\n",
+				"-func Old() {}\n",
+				"+func New() {}\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.file.changed() {
+				t.Fatal("changed() = false, want true")
+			}
+
+			got, err := tt.file.diff()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("diff() = %q, must contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_renderAside(t *testing.T) {
+	chdirTestModule(t, map[string]string{
+		"domain/user.go": testDomainUser,
+	})
+	args := testGenerateArgs(t)
+
+	prj, err := args.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := renderAside(prj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].path != filepath.Join("domain", "user_convgen.go") {
+		t.Fatalf("renderAside() = %v, want domain/user_convgen.go only", files)
+	}
+	if files[0].current != nil || !files[0].changed() {
+		t.Errorf("file missing in the project must be changed")
+	}
+	if _, err := os.Stat(files[0].path); !os.IsNotExist(err) {
+		t.Errorf("renderAside() must not write project files, stat error: %v", err)
+	}
+
+	if err := os.WriteFile(files[0].path, files[0].content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	prj, err = args.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err = renderAside(prj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files[0].changed() {
+		t.Errorf("file with the same content must not be changed")
+	}
+}
@@ -0,0 +1,91 @@
+// Package convgen поддержка кода сгенерированного awesome-converter
+package convgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConversionError ошибка конвертации значения поля
+type ConversionError struct {
+	// Path путь к значению в исходной структуре, например Items[3].Labels["env"]
+	Path string
+	// PrimaryType, SecondaryType типы значения в primary и secondary структурах
+	PrimaryType   string
+	SecondaryType string
+	// Value значение, которое не удалось сконвертировать
+	Value any
+	// Cause причина ошибки
+	Cause error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("convert %s: %s", e.Path, e.Cause)
+}
+
+// Unwrap причина ошибки
+func (e *ConversionError) Unwrap() error {
+	return e.Cause
+}
+
+// Wrap ошибка конвертации значения value по пути path с данной причиной. Если причина сама является ошибкой
+// конвертации, например, вложенной структуры, то её путь дополняется путём path, остальные поля сохраняются.
+func Wrap(cause error, path, primType, secType string, value any) error {
+	if errs, ok := cause.(Errors); ok {
+		res := make(Errors, len(errs))
+		for i, err := range errs {
+			res[i] = Wrap(err, path, primType, secType, value)
+		}
+		return res
+	}
+
+	var nested *ConversionError
+	if errors.As(cause, &nested) {
+		res := *nested
+		res.Path = joinPath(path, nested.Path)
+		return &res
+	}
+
+	return &ConversionError{
+		Path:          path,
+		PrimaryType:   primType,
+		SecondaryType: secType,
+		Value:         value,
+		Cause:         cause,
+	}
+}
+
+// Errors ошибки конвертации нескольких значений, возвращаются сгенерированным кодом в режиме сбора ошибок
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap ошибки отдельных значений для errors.Is и errors.As начиная с go1.20
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Append добавление ошибки err к списку errs, ошибки вложенных списков добавляются по отдельности
+func Append(errs Errors, err error) Errors {
+	if nested, ok := err.(Errors); ok {
+		return append(errs, nested...)
+	}
+
+	return append(errs, err)
+}
+
+func joinPath(outer, inner string) string {
+	if inner == "" || strings.HasPrefix(inner, "[") {
+		return outer + inner
+	}
+
+	return outer + "." + inner
+}
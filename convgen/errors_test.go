@@ -0,0 +1,77 @@
+package convgen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("invalid UUID length: 3")
+
+	tests := []struct {
+		name     string
+		err      error
+		path     string
+		wantPath string
+		wantMsg  string
+	}{
+		{
+			name:     "field",
+			err:      cause,
+			path:     "Owner",
+			wantPath: "Owner",
+			wantMsg:  "convert Owner: invalid UUID length: 3",
+		},
+		{
+			name:     "nested",
+			err:      Wrap(cause, `Labels["env"]`, "uuid.UUID", "string", "abc"),
+			path:     "Items[3]",
+			wantPath: `Items[3].Labels["env"]`,
+			wantMsg:  `convert Items[3].Labels["env"]: invalid UUID length: 3`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Wrap(tt.err, tt.path, "uuid.UUID", "string", "abc")
+
+			var convErr *ConversionError
+			if !errors.As(err, &convErr) {
+				t.Fatalf("ConversionError expected, got %T", err)
+			}
+			if convErr.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", convErr.Path, tt.wantPath)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+			if !errors.Is(err, cause) {
+				t.Error("cause is lost")
+			}
+		})
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	var errs Errors
+	errs = Append(errs, Wrap(errors.New("unknown value 7"), "Kind", "Kind", "pb.Kind", 7))
+	errs = Append(errs, Errors{
+		Wrap(errors.New("invalid UUID length: 3"), "Owner", "uuid.UUID", "string", "abc"),
+		Wrap(errors.New("value 300 cannot be represented as uint8"), "Size", "uint8", "int32", 300),
+	})
+
+	err := Wrap(errs, "Items[3]", "*Item", "*pb.Item", nil)
+	got, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("Errors expected, got %T", err)
+	}
+
+	wantPaths := []string{"Items[3].Kind", "Items[3].Owner", "Items[3].Size"}
+	if len(got) != len(wantPaths) {
+		t.Fatalf("got %d errors, want %d", len(got), len(wantPaths))
+	}
+	for i, want := range wantPaths {
+		if path := got[i].(*ConversionError).Path; path != want {
+			t.Errorf("errors[%d].Path = %q, want %q", i, path, want)
+		}
+	}
+}
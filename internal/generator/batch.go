@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"path/filepath"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+)
+
+// Pair описание пары структур для генерации конвертаций между ними
+type Pair struct {
+	// PrimaryPkg путь пакета primary-структуры
+	PrimaryPkg string
+	// PrimaryName название primary-структуры
+	PrimaryName string
+	// SecondaryPkg путь пакета secondary-структуры
+	SecondaryPkg string
+	// SecondaryName название secondary-структуры
+	SecondaryName string
+	// Method название метода конвертации primary → secondary, генерируется свободная функция если не задано
+	Method string
+	// Options опции генерации для данной пары
+	Options []Option
+}
+
+// NewBatch конструктор генераторов для набора пар структур. Все задействованные пакеты загружаются за один раз.
+func NewBatch(pairs []Pair) ([]*Generator, error) {
+	var loader Generator
+	var descrs []structDescription
+	for _, pair := range pairs {
+		descrs = append(
+			descrs,
+			structDescription{
+				pkg:  pair.PrimaryPkg,
+				name: pair.PrimaryName,
+			},
+			structDescription{
+				pkg:  pair.SecondaryPkg,
+				name: pair.SecondaryName,
+			},
+		)
+	}
+
+	structs, err := loader.getOrigStructs(descrs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "look for primary and secondary structs definitions")
+	}
+
+	var res []*Generator
+	outputs := map[string]*Generator{}
+	nested := newNestedConversions()
+	for i, pair := range pairs {
+		g := &Generator{
+			prim:   structs[descrs[2*i].String()],
+			sec:    structs[descrs[2*i+1].String()],
+			method: pair.Method,
+			nested: nested,
+			fs:     loader.fs,
+		}
+		for _, opt := range pair.Options {
+			opt(g)
+		}
+
+		if err := g.checkDirections(); err != nil {
+			return nil, errors.Wrapf(err, "check conversions of %s and %s", g.prim, g.sec)
+		}
+
+		if err := g.checkManualMatches(); err != nil {
+			return nil, errors.Wrapf(err, "check manual fields matches of %s and %s", g.prim, g.sec)
+		}
+
+		output := filepath.Join(g.prim.Obj().Pkg().Path(), g.fileName())
+		if prev, ok := outputs[output]; ok {
+			return nil, errors.Newf(
+				"conversions of %s and %s would be generated into the same file %s as conversions of %s and %s",
+				g.prim,
+				g.sec,
+				output,
+				prev.prim,
+				prev.sec,
+			)
+		}
+		outputs[output] = g
+		nested.add(g)
+
+		res = append(res, g)
+	}
+
+	return res, nil
+}
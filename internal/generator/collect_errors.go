@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"go/types"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// В режиме сбора ошибок (WithCollectErrors) конвертация каждого поля, которая может завершиться ошибкой, выполняется
+// в замыкании возвращающем только ошибку, ошибки замыканий собираются в список convgen.Errors возвращаемый в конце
+// конвертации. С библиотеками ошибок pkg и std ошибки собираются в []error и объединяются errors.Join.
+
+// collectInit объявление списка ошибок конвертации
+func (g *Generator) collectInit(r *matiss.GoRenderer) {
+	if !g.collectErrors {
+		return
+	}
+
+	if g.errorsBackend != ErrorsUCS {
+		r.L(`var errs []error`)
+		return
+	}
+
+	r.Imports().Add(convgenPath).Ref("convgen")
+	r.L(`var errs $convgen.Errors`)
+}
+
+// collectResult возврат собранных ошибок конвертации
+func (g *Generator) collectResult(r *matiss.GoRenderer) {
+	if !g.collectErrors {
+		return
+	}
+
+	errs := "errs"
+	switch g.errorsBackend {
+	case ErrorsStd:
+		g.importErrors(r)
+		errs = r.S(`$errors.Join(errs...)`)
+	case ErrorsPkg:
+		// в github.com/pkg/errors нет Join
+		r.Imports().Add("errors").Ref("stderrors")
+		errs = r.S(`$stderrors.Join(errs...)`)
+	}
+
+	r.N()
+	r.L(`if len(errs) > 0 {`)
+	r.L(`    return nil, $0`, errs)
+	r.L(`}`)
+}
+
+// collectAppend оператор генерируемого кода добавляющий ошибку err в список ошибок
+func (g *Generator) collectAppend(r *matiss.GoRenderer, err string) string {
+	if g.errorsBackend != ErrorsUCS {
+		return r.S(`errs = append(errs, $0)`, err)
+	}
+
+	return r.S(`errs = $convgen.Append(errs, $0)`, err)
+}
+
+// collectBegin начало конвертации поля, ошибка которой собирается, если конвертация не может завершиться ошибкой,
+// то замыкание не нужно
+func (g *Generator) collectBegin(r *matiss.GoRenderer, canFail bool) {
+	if !g.collectErrors || !canFail {
+		return
+	}
+
+	g.collecting = true
+	r.L(`if err := func() error {`)
+}
+
+// collectEnd завершение конвертации поля начатой collectBegin
+func (g *Generator) collectEnd(r *matiss.GoRenderer) {
+	if !g.collecting {
+		return
+	}
+
+	g.collecting = false
+	r.L(`    return nil`)
+	r.L(`}(); err != nil {`)
+	r.L(`    $0`, g.collectAppend(r, "err"))
+	r.L(`}`)
+}
+
+// errReturn возврат ошибки err из конвертации значения
+func (g *Generator) errReturn(err string) string {
+	if g.collecting {
+		return "return " + err
+	}
+
+	return "return nil, " + err
+}
+
+// returnError возврат ошибки конвертации значения, см. conversionError
+func (g *Generator) returnError(
+	r *matiss.GoRenderer,
+	whoami valuePath,
+	cause string,
+	value string,
+	srcType types.Type,
+	dstType types.Type,
+) string {
+	return g.errReturn(g.conversionError(r, whoami, cause, value, srcType, dstType))
+}
+
+// fieldFailure обработка ошибки err конвертации поля вне замыкания: возврат либо добавление в список ошибок
+func (g *Generator) fieldFailure(r *matiss.GoRenderer, err string) {
+	if g.collectErrors {
+		r.L(`$0`, g.collectAppend(r, err))
+		return
+	}
+
+	r.L(`return nil, $0`, err)
+}
+
+// canFail проверка того, что конвертация значения типа srcType в dstType может завершиться ошибкой
+func (g *Generator) canFail(descr FieldMatchDescription, dstType, srcType types.Type) bool {
+	switch v := descr.(type) {
+	case *FieldMatchConversion:
+		return conversionSignature(v, dstType, srcType).Results().Len() == 2
+	case *FieldMatchNested:
+		return true
+	case *FieldMatchWellKnown:
+		return v.FromProto && v.wk.checkValid
+	case *FieldMatchUUID:
+		return v.FromString
+	case *FieldMatchText:
+		return true
+	case *FieldMatchEnum:
+		return g.enumFallback(v.Secondary).kind == enumFallbackError
+	case *FieldMatchCastable:
+		return v.Narrowing && g.checkNarrowing
+	case *FieldMatchSlice:
+		return g.canFail(
+			v.Elem,
+			unpointer(dstType).(*types.Slice).Elem(),
+			unpointer(srcType).(*types.Slice).Elem(),
+		)
+	case *FieldMatchMap:
+		dst, src := unpointer(dstType).(*types.Map), unpointer(srcType).(*types.Map)
+		if _, ok := v.Key.(*FieldMatchDirect); !ok || !types.Identical(dst.Key(), src.Key()) {
+			// сконвертированные ключи проверяются на совпадение, см. convertMapItems
+			if castable, ok := v.Key.(*FieldMatchCastable); !ok || castable.Narrowing {
+				return true
+			}
+		}
+		return g.canFail(v.Elem, dst.Elem(), src.Elem())
+	default:
+		return false
+	}
+}
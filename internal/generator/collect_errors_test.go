@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerator_collectErrorsGenerated(t *testing.T) {
+	files := runGenerated(t, "collect", Pair{
+		PrimaryPkg:    testdataPath + "/collect",
+		PrimaryName:   "Order",
+		SecondaryPkg:  testdataPath + "/collect/pb",
+		SecondaryName: "Order",
+		Options:       []Option{WithCollectErrors(), WithNarrowingChecks()},
+	})
+	content := files["internal/generator/testdata/collect/collect_convgen.go"]
+
+	// конвертации, которые не могут завершиться ошибкой, выполняются без замыканий: на верхнем уровне функции
+	for _, want := range []string{
+		"\n\tres.Name = x.Name\n",
+		"\n\tres.ID = int64(x.ID)\n",
+		"\n\tres.City = x.City\n",
+		"\n\tswitch v := x.Contact.(type) {\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code must contain %q outside of closures:\n%s", want, content)
+		}
+	}
+
+	// замыкания полей ID, Address и oneof-а Contact, а так же поля Zip вложенной структуры
+	if got := strings.Count(content, "if err := func() error {"); got != 5 {
+		t.Errorf("generated code contains %d closures, want 5:\n%s", got, content)
+	}
+}
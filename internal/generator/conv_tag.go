@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+)
+
+// convTagName название тега полей primary-структуры управляющего сопоставлением
+const convTagName = "conv"
+
+// convTag разобранное значение тега conv. Допустимые формы:
+//   • conv:"sec_name" — поле сопоставляется полю secondary-структуры с данным именем
+//   • conv:"-"        — поле исключается из конвертации в обе стороны
+//   • conv:"-,to"     — поле исключается из конвертации primary → secondary
+//   • conv:"-,from"   — поле исключается из конвертации secondary → primary
+type convTag struct {
+	// name имя поля secondary-структуры, пусто если не задано
+	name string
+	// excludeTo поле исключено из конвертации primary → secondary
+	excludeTo bool
+	// excludeFrom поле исключено из конвертации secondary → primary
+	excludeFrom bool
+}
+
+// parseConvTag разбор тега conv из тегов поля
+func parseConvTag(tags string) (convTag, error) {
+	value, ok := reflect.StructTag(tags).Lookup(convTagName)
+	if !ok {
+		return convTag{}, nil
+	}
+
+	name, direction, hasDirection := strings.Cut(value, ",")
+	switch name {
+	case "":
+		return convTag{}, errors.Newf("empty %s tag value", convTagName)
+	case "-":
+		switch {
+		case !hasDirection:
+			return convTag{
+				excludeTo:   true,
+				excludeFrom: true,
+			}, nil
+		case direction == "to":
+			return convTag{
+				excludeTo: true,
+			}, nil
+		case direction == "from":
+			return convTag{
+				excludeFrom: true,
+			}, nil
+		default:
+			return convTag{}, errors.Newf("unknown exclusion direction '%s', must be either 'to' or 'from'", direction)
+		}
+	default:
+		if hasDirection {
+			return convTag{}, errors.Newf("unexpected options '%s' after field name %s", direction, name)
+		}
+
+		return convTag{
+			name: name,
+		}, nil
+	}
+}
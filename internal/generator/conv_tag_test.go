@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"testing"
+)
+
+func Test_parseConvTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    string
+		want    convTag
+		wantErr bool
+	}{
+		{
+			name: "no-tag",
+			tags: `json:"id"`,
+			want: convTag{},
+		},
+		{
+			name: "rename",
+			tags: `json:"region_id" conv:"location"`,
+			want: convTag{name: "location"},
+		},
+		{
+			name: "exclude",
+			tags: `conv:"-"`,
+			want: convTag{excludeTo: true, excludeFrom: true},
+		},
+		{
+			name: "exclude-to",
+			tags: `conv:"-,to"`,
+			want: convTag{excludeTo: true},
+		},
+		{
+			name: "exclude-from",
+			tags: `conv:"-,from"`,
+			want: convTag{excludeFrom: true},
+		},
+		{
+			name:    "empty",
+			tags:    `conv:""`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown-direction",
+			tags:    `conv:"-,both"`,
+			wantErr: true,
+		},
+		{
+			name:    "rename-with-options",
+			tags:    `conv:"location,to"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConvTag(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConvTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseConvTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package generator
 
-import "go/types"
+import (
+	"go/types"
+	"sort"
+)
 
 type enumDescription struct {
 	orig    types.Type
@@ -58,3 +61,14 @@ func (g *Generator) getEnumInfo(x types.Type) *enumDescription {
 		isProto: isProto,
 	}
 }
+
+// names отсортированные названия констант перечисления
+func (d *enumDescription) names() []string {
+	res := make([]string, 0, len(d.values))
+	for name := range d.values {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// EnumFallback поведение конвертации перечислений для значений не имеющих пары
+type EnumFallback struct {
+	kind enumFallbackKind
+	// value название константы перечисления-приёмника для enumFallbackDefault, если не задано, то используется
+	// константа с нулевым значением
+	value string
+}
+
+type enumFallbackKind int
+
+const (
+	// enumFallbackError возврат ошибки
+	enumFallbackError enumFallbackKind = iota
+	// enumFallbackDefault значение по умолчанию перечисления-приёмника
+	enumFallbackDefault
+	// enumFallbackRaw приведение значения к типу перечисления-приёмника как есть
+	enumFallbackRaw
+)
+
+// ParseEnumFallback разбор поведения для значений перечислений не имеющих пары:
+//   • error — возврат ошибки, поведение по умолчанию
+//   • default — константа перечисления-приёмника с нулевым значением, например *_UNSPECIFIED протобуфа
+//   • default:NAME — константа NAME перечисления-приёмника
+//   • raw — приведение значения к типу перечисления-приёмника как есть
+func ParseEnumFallback(s string) (EnumFallback, error) {
+	switch {
+	case s == "error":
+		return EnumFallback{kind: enumFallbackError}, nil
+	case s == "raw":
+		return EnumFallback{kind: enumFallbackRaw}, nil
+	case s == "default":
+		return EnumFallback{kind: enumFallbackDefault}, nil
+	case strings.HasPrefix(s, "default:") && len(s) > len("default:"):
+		return EnumFallback{
+			kind:  enumFallbackDefault,
+			value: strings.TrimPrefix(s, "default:"),
+		}, nil
+	default:
+		return EnumFallback{}, errors.Newf(
+			"invalid enum fallback '%s', must be one of error, raw, default or default:NAME",
+			s,
+		)
+	}
+}
+
+func (f EnumFallback) String() string {
+	switch f.kind {
+	case enumFallbackRaw:
+		return "raw"
+	case enumFallbackDefault:
+		if f.value != "" {
+			return "default:" + f.value
+		}
+		return "default"
+	default:
+		return "error"
+	}
+}
+
+// enumFallback поведение для значений не имеющих пары при конвертации в данное перечисление. Перечисление ищется
+// сначала по полному названию типа вида <pkg-path>.<name>, затем по названию без пакета.
+func (g *Generator) enumFallback(dst *enumDescription) EnumFallback {
+	if f, ok := g.enumFallbacks[dst.orig.String()]; ok {
+		return f
+	}
+	if f, ok := g.enumFallbacks[dst.orig.(*types.Named).Obj().Name()]; ok {
+		return f
+	}
+
+	return g.enumFallbackAll
+}
+
+// defaultConst константа перечисления-приёмника используемая как значение по умолчанию
+func (f EnumFallback) defaultConst(dst *enumDescription) (*types.Const, error) {
+	if f.value != "" {
+		c, ok := dst.values[f.value]
+		if !ok {
+			return nil, errors.Newf("%s has no constant %s", dst.orig, f.value)
+		}
+
+		return c, nil
+	}
+
+	zero := constant.MakeInt64(0)
+	if dst.isString() {
+		zero = constant.MakeString("")
+	}
+	for _, name := range dst.names() {
+		if c := dst.values[name]; constant.Compare(c.Val(), token.EQL, zero) {
+			return c, nil
+		}
+	}
+
+	return nil, errors.Newf("%s has no constant with zero value", dst.orig)
+}
+
+// check проверка применимости поведения к конвертации из перечисления src в dst
+func (f EnumFallback) check(dst, src *enumDescription) error {
+	switch f.kind {
+	case enumFallbackDefault:
+		if _, err := f.defaultConst(dst); err != nil {
+			return errors.Wrap(err, "look for default value")
+		}
+	case enumFallbackRaw:
+		if dst.isString() != src.isString() {
+			return errors.Newf("raw values of %s cannot be cast to %s", src.orig, dst.orig)
+		}
+	}
+
+	return nil
+}
+
+// checkEnumFallbacks проверка применимости поведения для значений не имеющих пары ко всем конвертациям перечислений
+// пары структур
+func (g *Generator) checkEnumFallbacks(matches []fieldMatchInfo, oos []fieldSecondaryOneof) error {
+	var descrs []FieldMatchDescription
+	for _, m := range matches {
+		descrs = append(descrs, m.descr)
+	}
+	for _, oo := range oos {
+		for _, b := range oo.branches {
+			descrs = append(descrs, b.descr)
+		}
+	}
+
+	for _, descr := range descrs {
+		for _, e := range enumMatches(descr) {
+			if !g.noTo {
+				if err := g.enumFallback(e.Secondary).check(e.Secondary, e.Primary); err != nil {
+					return errors.Wrapf(err, "apply enum fallback to %s → %s", e.Primary.orig, e.Secondary.orig)
+				}
+			}
+
+			if !g.noFrom {
+				if err := g.enumFallback(e.Primary).check(e.Primary, e.Secondary); err != nil {
+					return errors.Wrapf(err, "apply enum fallback to %s → %s", e.Secondary.orig, e.Primary.orig)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// enumMatches сопоставления перечислений в описании, включая элементы слайсов и словарей
+func enumMatches(descr FieldMatchDescription) []*FieldMatchEnum {
+	switch v := descr.(type) {
+	case *FieldMatchEnum:
+		return []*FieldMatchEnum{v}
+	case *FieldMatchSlice:
+		return enumMatches(v.Elem)
+	case *FieldMatchMap:
+		return append(enumMatches(v.Key), enumMatches(v.Elem)...)
+	default:
+		return nil
+	}
+}
+
+// convertEnumFallback генерация обработки значения src перечисления не имеющего пары
+func (g *Generator) convertEnumFallback(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	v *FieldMatchEnum,
+	whoami valuePath,
+) {
+	fallback := g.enumFallback(v.Secondary)
+	switch fallback.kind {
+	case enumFallbackDefault:
+		// применимость проверена в checkEnumFallbacks
+		c, _ := fallback.defaultConst(v.Secondary)
+		assignSafe(r, dst, dstType, g.constName(r, c), c.Type(), true)
+	case enumFallbackRaw:
+		raw := r.S(`$0($1)`, g.typeName(r, v.Secondary.orig), deref(src, srcType))
+		assignSafe(r, dst, dstType, raw, v.Secondary.orig, true)
+	default:
+		cause := g.errNewf(r, "unknown value %v", deref(src, srcType))
+		r.L(`$0`, g.returnError(r, whoami, cause, deref(src, srcType), srcType, dstType))
+	}
+}
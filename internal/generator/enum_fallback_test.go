@@ -0,0 +1,88 @@
+package generator
+
+import "testing"
+
+func TestParseEnumFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    EnumFallback
+		wantErr bool
+	}{
+		{
+			name: "error",
+			s:    "error",
+			want: EnumFallback{kind: enumFallbackError},
+		},
+		{
+			name: "raw",
+			s:    "raw",
+			want: EnumFallback{kind: enumFallbackRaw},
+		},
+		{
+			name: "default",
+			s:    "default",
+			want: EnumFallback{kind: enumFallbackDefault},
+		},
+		{
+			name: "default-name",
+			s:    "default:RegionKind_REGION_KIND_UNSPECIFIED",
+			want: EnumFallback{kind: enumFallbackDefault, value: "RegionKind_REGION_KIND_UNSPECIFIED"},
+		},
+		{
+			name:    "default-empty-name",
+			s:       "default:",
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			s:       "zero",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			s:       "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEnumFallback(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEnumFallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseEnumFallback() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.s {
+				t.Errorf("String() = %v, want %v", got.String(), tt.s)
+			}
+		})
+	}
+}
+
+func TestGenerator_enumFallbackGenerated(t *testing.T) {
+	runGenerated(
+		t,
+		"fallback",
+		testdataPair(
+			"fallback",
+			"Lamp",
+			"LampDefault",
+			WithOutput("default_convgen.go"),
+			WithEnumFallback(EnumFallback{kind: enumFallbackDefault}),
+		),
+		testdataPair(
+			"fallback",
+			"Lamp",
+			"LampRaw",
+			WithOutput("raw_convgen.go"),
+			WithEnumFallback(EnumFallback{kind: enumFallbackRaw}),
+		),
+		testdataPair("fallback", "Lamp", "LampError", WithOutput("error_convgen.go")),
+	)
+}
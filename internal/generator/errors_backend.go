@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// ErrorsBackend библиотека ошибок используемая сгенерированным кодом
+type ErrorsBackend int
+
+const (
+	// ErrorsUCS пакет errors UCS-COMMON, используется по умолчанию
+	ErrorsUCS ErrorsBackend = iota
+	// ErrorsPkg пакет github.com/pkg/errors
+	ErrorsPkg
+	// ErrorsStd стандартные errors и fmt.Errorf с обёртками через %w
+	ErrorsStd
+)
+
+// ParseErrorsBackend разбор библиотеки ошибок:
+//   • ucs — пакет errors UCS-COMMON, поведение по умолчанию
+//   • pkg — пакет github.com/pkg/errors
+//   • std — стандартные errors и fmt.Errorf
+func ParseErrorsBackend(s string) (ErrorsBackend, error) {
+	switch s {
+	case "ucs":
+		return ErrorsUCS, nil
+	case "pkg":
+		return ErrorsPkg, nil
+	case "std":
+		return ErrorsStd, nil
+	default:
+		return 0, errors.Newf("invalid errors backend '%s', must be one of ucs, pkg or std", s)
+	}
+}
+
+func (b ErrorsBackend) String() string {
+	switch b {
+	case ErrorsPkg:
+		return "pkg"
+	case ErrorsStd:
+		return "std"
+	default:
+		return "ucs"
+	}
+}
+
+// importErrors импорт пакета ошибок под именем errors
+func (g *Generator) importErrors(r *matiss.GoRenderer) {
+	switch g.errorsBackend {
+	case ErrorsPkg:
+		r.Imports().Add("github.com/pkg/errors").Ref("errors")
+	case ErrorsStd:
+		r.Imports().Add("errors").Ref("errors")
+	default:
+		r.Imports().Errors().Ref("errors")
+	}
+}
+
+// errNew выражение генерируемого кода с новой ошибкой с данным сообщением
+func (g *Generator) errNew(r *matiss.GoRenderer, msg string) string {
+	g.importErrors(r)
+	return r.S(`$errors.New($0)`, strconv.Quote(msg))
+}
+
+// errNewf выражение генерируемого кода с новой ошибкой с сообщением по формату в стиле fmt и выражениям args
+func (g *Generator) errNewf(r *matiss.GoRenderer, format string, args ...string) string {
+	format = strconv.Quote(format)
+	switch g.errorsBackend {
+	case ErrorsPkg:
+		g.importErrors(r)
+		return r.S(`$errors.Errorf($0, $1)`, format, strings.Join(args, ", "))
+	case ErrorsStd:
+		r.Imports().Add("fmt").Ref("fmt")
+		return r.S(`$fmt.Errorf($0, $1)`, format, strings.Join(args, ", "))
+	default:
+		g.importErrors(r)
+		return r.S(`$errors.Newf($0, $1)`, format, strings.Join(args, ", "))
+	}
+}
+
+// errWrap выражение генерируемого кода с ошибкой err обёрнутой данным сообщением
+func (g *Generator) errWrap(r *matiss.GoRenderer, err string, msg string) string {
+	if g.errorsBackend == ErrorsStd {
+		r.Imports().Add("fmt").Ref("fmt")
+		return r.S(`$fmt.Errorf($0, $1)`, strconv.Quote(msg+": %w"), err)
+	}
+
+	g.importErrors(r)
+	return r.S(`$errors.Wrap($0, $1)`, err, strconv.Quote(msg))
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseErrorsBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    ErrorsBackend
+		wantErr bool
+	}{
+		{
+			name: "ucs",
+			s:    "ucs",
+			want: ErrorsUCS,
+		},
+		{
+			name: "pkg",
+			s:    "pkg",
+			want: ErrorsPkg,
+		},
+		{
+			name: "std",
+			s:    "std",
+			want: ErrorsStd,
+		},
+		{
+			name:    "unknown",
+			s:       "xerrors",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseErrorsBackend(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseErrorsBackend() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseErrorsBackend() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.s {
+				t.Errorf("String() = %v, want %v", got.String(), tt.s)
+			}
+		})
+	}
+}
+
+func TestGenerator_errorsBackendsGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend ErrorsBackend
+		want    []string
+	}{
+		{
+			name:    "std",
+			backend: ErrorsStd,
+			want: []string{
+				`fmt.Errorf("convert ID: %w", fmt.Errorf("value %v cannot be represented as int32", x.ID))`,
+				`return nil, errors.Join(errs...)`,
+			},
+		},
+		{
+			name:    "pkg",
+			backend: ErrorsPkg,
+			want: []string{
+				`errors.Wrap(errors.Errorf("value %v cannot be represented as int32", x.ID), "convert ID")`,
+				`return nil, stderrors.Join(errs...)`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, _, err := generateTestdata(t, Pair{
+				PrimaryPkg:    testdataPath + "/collect",
+				PrimaryName:   "Order",
+				SecondaryPkg:  testdataPath + "/collect/pb",
+				SecondaryName: "Order",
+				Options:       []Option{WithCollectErrors(), WithNarrowingChecks(), WithErrorsBackend(tt.backend)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content := files["internal/generator/testdata/collect/collect_convgen.go"]
+			if strings.Contains(content, convgenPath) {
+				t.Errorf("generated code must not import %s:\n%s", convgenPath, content)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("generated code must contain %s:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_stdErrorsGenerated(t *testing.T) {
+	// пути к значениям с ключами словарей и сообщения ошибок сохраняются без пакета convgen
+	files := runGenerated(
+		t,
+		"mapkeys",
+		testdataPair(
+			"mapkeys",
+			"Zone",
+			"ZonePB",
+			WithErrorsBackend(ErrorsStd),
+			WithEnumMapping(map[string]string{"KindPrimary": "KindPBMain", "KindBackup": "KindPBMain"}, nil),
+		),
+	)
+	for name, content := range files {
+		if strings.Contains(content, convgenPath) {
+			t.Errorf("%s must not import %s:\n%s", name, convgenPath, content)
+		}
+	}
+}
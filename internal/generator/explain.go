@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"go/constant"
+	"go/types"
+)
+
+// Explanation результат сопоставления полей primary и secondary структур в пригодном для сериализации виде
+type Explanation struct {
+	Primary            string           `json:"primary"`
+	Secondary          string           `json:"secondary"`
+	Fields             []ExplainedField `json:"fields"`
+	Oneofs             []ExplainedOneof `json:"oneofs,omitempty"`
+	UnmatchedPrimary   []ExplainedVar   `json:"unmatched_primary"`
+	UnmatchedSecondary []ExplainedVar   `json:"unmatched_secondary"`
+}
+
+// ExplainedVar описание поля структуры
+type ExplainedVar struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Position string `json:"position"`
+}
+
+// ExplainedField сопоставление поля primary-структуры
+type ExplainedField struct {
+	Primary     ExplainedVar    `json:"primary"`
+	Secondary   *ExplainedVar   `json:"secondary,omitempty"`
+	Match       *ExplainedMatch `json:"match"`
+	Manual      bool            `json:"manual,omitempty"`
+	ExcludeTo   bool            `json:"exclude_to,omitempty"`
+	ExcludeFrom bool            `json:"exclude_from,omitempty"`
+}
+
+// ExplainedOneof сопоставление oneof-а secondary-структуры полям primary-структуры
+type ExplainedOneof struct {
+	Secondary ExplainedVar      `json:"secondary"`
+	Branches  []ExplainedBranch `json:"branches"`
+}
+
+// ExplainedBranch сопоставление ветви oneof-а полю primary-структуры
+type ExplainedBranch struct {
+	Branch    string          `json:"branch"`
+	Primary   ExplainedVar    `json:"primary"`
+	Secondary ExplainedVar    `json:"secondary"`
+	Match     *ExplainedMatch `json:"match"`
+}
+
+// ExplainedMatch описание FieldMatchDescription
+type ExplainedMatch struct {
+	// Kind вид соответствия: no-match, direct, conversion, enum, castable, slice, map, nested, well-known, uuid,
+	// text
+	Kind        string                `json:"kind"`
+	Description string                `json:"description"`
+	Conversion  *FieldMatchConversion `json:"conversion,omitempty"`
+	Castable    *FieldMatchCastable   `json:"castable,omitempty"`
+	Enum        *ExplainedEnum        `json:"enum,omitempty"`
+	Nested      *FieldMatchNested     `json:"nested,omitempty"`
+	WellKnown   *FieldMatchWellKnown  `json:"well_known,omitempty"`
+	UUID        *FieldMatchUUID       `json:"uuid,omitempty"`
+	Text        *FieldMatchText       `json:"text,omitempty"`
+	Key         *ExplainedMatch       `json:"key,omitempty"`
+	Elem        *ExplainedMatch       `json:"elem,omitempty"`
+}
+
+// ExplainedEnum описание сопоставленных перечислений
+type ExplainedEnum struct {
+	Primary   string   `json:"primary"`
+	Secondary string   `json:"secondary"`
+	Values    []string `json:"values"`
+	ByName    bool     `json:"by_name,omitempty"`
+	Manual    bool     `json:"manual,omitempty"`
+	// ProtoNames значения перечисления-строки являются названиями значений перечисления протобуфа
+	ProtoNames bool `json:"proto_names,omitempty"`
+	// Mapping сопоставление констант primary-перечисления константам secondary
+	Mapping map[string]string `json:"mapping"`
+	// BackMapping сопоставление констант secondary-перечисления константам primary, если оно задано вручную
+	BackMapping map[string]string `json:"back_mapping,omitempty"`
+}
+
+// Explain сопоставление полей primary и secondary структур без генерации кода
+func (g *Generator) Explain() *Explanation {
+	matches, oos := g.getFieldsMatches(g.manual)
+
+	res := &Explanation{
+		Primary:            g.prim.String(),
+		Secondary:          g.sec.String(),
+		UnmatchedPrimary:   []ExplainedVar{},
+		UnmatchedSecondary: []ExplainedVar{},
+	}
+
+	for _, m := range matches {
+		field := ExplainedField{
+			Primary:     g.explainVar(m.prim),
+			Match:       explainMatch(m.descr),
+			Manual:      m.manual,
+			ExcludeTo:   m.excludeTo,
+			ExcludeFrom: m.excludeFrom,
+		}
+		if m.sec != nil {
+			sec := g.explainVar(m.sec)
+			field.Secondary = &sec
+		}
+
+		res.Fields = append(res.Fields, field)
+	}
+
+	for _, oo := range oos {
+		oneof := ExplainedOneof{
+			Secondary: g.explainVar(oo.sec),
+		}
+		for _, b := range oo.branches {
+			oneof.Branches = append(oneof.Branches, ExplainedBranch{
+				Branch:    b.branch,
+				Primary:   g.explainVar(b.prim),
+				Secondary: g.explainVar(b.sec),
+				Match:     explainMatch(b.descr),
+			})
+		}
+
+		res.Oneofs = append(res.Oneofs, oneof)
+	}
+
+	for _, f := range unmatchedPrimaryFields(matches) {
+		res.UnmatchedPrimary = append(res.UnmatchedPrimary, g.explainVar(f))
+	}
+
+	for _, f := range g.uncoveredSecondaryFields(matches, oos) {
+		res.UnmatchedSecondary = append(res.UnmatchedSecondary, g.explainVar(f))
+	}
+
+	return res
+}
+
+// Report вывод информации о сопоставлении полей primary и secondary структур без генерации кода
+func (g *Generator) Report() {
+	matches, oos := g.getFieldsMatches(g.manual)
+	g.reportMatchingInfo(matches, oos)
+}
+
+func (g *Generator) explainVar(v *types.Var) ExplainedVar {
+	return ExplainedVar{
+		Name:     v.Name(),
+		Type:     v.Type().String(),
+		Position: g.fs.Position(v.Pos()).String(),
+	}
+}
+
+func explainMatch(descr FieldMatchDescription) *ExplainedMatch {
+	res := &ExplainedMatch{
+		Description: descr.String(),
+	}
+
+	switch v := descr.(type) {
+	case *FieldMatchNoMatch:
+		res.Kind = "no-match"
+	case *FieldMatchDirect:
+		res.Kind = "direct"
+	case *FieldMatchConversion:
+		res.Kind = "conversion"
+		res.Conversion = v
+	case *FieldMatchEnum:
+		res.Kind = "enum"
+		res.Enum = &ExplainedEnum{
+			Primary:    v.Primary.orig.String(),
+			Secondary:  v.Secondary.orig.String(),
+			ByName:     v.ByName,
+			Manual:     v.Manual,
+			ProtoNames: v.ProtoNames,
+			Mapping:    map[string]string{},
+		}
+		for _, name := range v.Primary.names() {
+			res.Enum.Values = append(res.Enum.Values, explainConst(v.Primary.values[name]))
+		}
+		for _, m := range v.forward {
+			res.Enum.Mapping[m.from.Name()] = m.to.Name()
+		}
+		if v.Manual {
+			res.Enum.BackMapping = map[string]string{}
+			for _, m := range v.backward {
+				res.Enum.BackMapping[m.from.Name()] = m.to.Name()
+			}
+		}
+	case *FieldMatchCastable:
+		res.Kind = "castable"
+		if v.Narrowing || v.NarrowingBack {
+			res.Castable = v
+		}
+	case *FieldMatchSlice:
+		res.Kind = "slice"
+		res.Elem = explainMatch(v.Elem)
+	case *FieldMatchMap:
+		res.Kind = "map"
+		res.Key = explainMatch(v.Key)
+		res.Elem = explainMatch(v.Elem)
+	case *FieldMatchNested:
+		res.Kind = "nested"
+		res.Nested = v
+	case *FieldMatchWellKnown:
+		res.Kind = "well-known"
+		res.WellKnown = v
+	case *FieldMatchUUID:
+		res.Kind = "uuid"
+		res.UUID = v
+	case *FieldMatchText:
+		res.Kind = "text"
+		res.Text = v
+	}
+
+	return res
+}
+
+// explainConst значение константы перечисления: строки без кавычек, остальные значения в точном виде
+func explainConst(c *types.Const) string {
+	if c.Val().Kind() == constant.String {
+		return constant.StringVal(c.Val())
+	}
+
+	return c.Val().ExactString()
+}
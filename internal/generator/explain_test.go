@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"go/constant"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func Test_explainMatch_enumValues(t *testing.T) {
+	domain := types.NewPackage("example.com/domain", "domain")
+	pb := types.NewPackage("example.com/pb", "pb")
+
+	tests := []struct {
+		name string
+		prim *types.Named
+		sec  *types.Named
+		want []string
+	}{
+		{
+			name: "string-enum",
+			prim: newTestEnum(domain, "Region", types.Typ[types.String], map[string]constant.Value{
+				"RegionEU": constant.MakeString("eu"),
+				"RegionUS": constant.MakeString("us"),
+			}),
+			sec: newTestEnum(pb, "Region", types.Typ[types.String], map[string]constant.Value{
+				"Region_EU": constant.MakeString("EU"),
+				"Region_US": constant.MakeString("US"),
+			}),
+			want: []string{"eu", "us"},
+		},
+		{
+			name: "int-enum",
+			prim: newTestEnum(domain, "Kind", types.Typ[types.Int], map[string]constant.Value{
+				"KindPrimary": constant.MakeInt64(1),
+				"KindBackup":  constant.MakeInt64(2),
+			}),
+			sec: newTestEnum(pb, "Kind", types.Typ[types.Int32], map[string]constant.Value{
+				"Kind_PRIMARY": constant.MakeInt64(1),
+				"Kind_BACKUP":  constant.MakeInt64(2),
+			}),
+			want: []string{"2", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{}
+			got := explainMatch(&FieldMatchEnum{
+				Primary:   g.getEnumInfo(tt.prim),
+				Secondary: g.getEnumInfo(tt.sec),
+			})
+			if !reflect.DeepEqual(got.Enum.Values, tt.want) {
+				t.Errorf("explainMatch() enum values = %q, want %q", got.Enum.Values, tt.want)
+			}
+		})
+	}
+}
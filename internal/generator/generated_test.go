@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+
+	// пакеты используемые только сгенерированным кодом testdata, без импорта здесь go mod tidy их вычистит
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testdataPath путь пакетов testdata со структурами для тестов сгенерированного кода
+const testdataPath = "awesome-converter/internal/generator/testdata"
+
+// testdataPair пара структур из пакета testdata/<dir>
+func testdataPair(dir, prim, sec string, opts ...Option) Pair {
+	return Pair{
+		PrimaryPkg:    testdataPath + "/" + dir,
+		PrimaryName:   prim,
+		SecondaryPkg:  testdataPath + "/" + dir,
+		SecondaryName: sec,
+		Options:       opts,
+	}
+}
+
+// generateTestdata генерация конвертаций для пар структур из testdata. Возвращает содержимое сгенерированных файлов по
+// их путям относительно корня модуля и сам корень модуля
+func generateTestdata(t *testing.T, pairs ...Pair) (map[string]string, string, error) {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain is not available")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := filepath.Abs(filepath.Join(wd, "..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	gens, err := NewBatch(pairs)
+	if err != nil {
+		return nil, root, err
+	}
+	prj, err := matiss.UpdateProject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range gens {
+		if err := g.Generate(prj); err != nil {
+			return nil, root, err
+		}
+	}
+
+	out := t.TempDir()
+	if err := prj.Render(matiss.Directory(out)); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	err = filepath.WalkDir(out, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files, root, nil
+}
+
+// runGenerated генерация конвертаций для пар структур и запуск тестов пакета testdata/<dir> поверх сгенерированного
+// кода. Возвращает содержимое сгенерированных файлов
+func runGenerated(t *testing.T, dir string, pairs ...Pair) map[string]string {
+	t.Helper()
+
+	files, root, err := generateTestdata(t, pairs...)
+	if err != nil {
+		t.Fatalf("generate conversions: %v", err)
+	}
+
+	// сгенерированные файлы подкладываются go test через overlay, дерево исходников не меняется
+	tmp := t.TempDir()
+	overlay := struct {
+		Replace map[string]string
+	}{
+		Replace: map[string]string{},
+	}
+	for rel, content := range files {
+		name := filepath.Join(tmp, strconv.Itoa(len(overlay.Replace))+"_"+filepath.Base(rel))
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		overlay.Replace[filepath.Join(root, filepath.FromSlash(rel))] = name
+	}
+
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlayPath := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "test", "-count=1", "-overlay", overlayPath, "./internal/generator/testdata/"+dir)
+	cmd.Dir = root
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("run tests of generated code: %v\n%s", err, output)
+	}
+
+	return files
+}
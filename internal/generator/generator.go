@@ -15,27 +15,22 @@ import (
 )
 
 // New конструктор генератора сущностей
-func New(primPkg, primName string, secPkg, secName, method string) (*Generator, error) {
-	var g Generator
-
-	prim := structDescription{
-		pkg:  primPkg,
-		name: primName,
-	}
-	sec := structDescription{
-		pkg:  secPkg,
-		name: secName,
-	}
-
-	structs, err := g.getOrigStructs(prim, sec)
+func New(primPkg, primName string, secPkg, secName, method string, opts ...Option) (*Generator, error) {
+	gens, err := NewBatch([]Pair{
+		{
+			PrimaryPkg:    primPkg,
+			PrimaryName:   primName,
+			SecondaryPkg:  secPkg,
+			SecondaryName: secName,
+			Method:        method,
+			Options:       opts,
+		},
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "look for primary and secondary structs definitions")
+		return nil, err
 	}
 
-	g.prim = structs[prim.String()]
-	g.sec = structs[sec.String()]
-	g.method = method
-	return &g, nil
+	return gens[0], nil
 }
 
 // Generator генератор преобразований структур
@@ -44,43 +39,206 @@ type Generator struct {
 	sec    *types.Named
 	method string
 
-	fs    *token.FileSet
-	fqsec int
+	// manual ручные сопоставления полей, ключи и значения приведены к виду matiss.Underscored
+	manual map[string]string
+	// manualInput ручные сопоставления полей в том виде, в котором они были заданы, для сообщений об ошибках
+	manualInput map[string]string
+	// ignored имена полей primary или secondary структур исключённых из конвертации: ключи в виде
+	// matiss.Underscored, значения в том виде, в котором они были заданы
+	ignored map[string]string
+	// output имя файла для генерируемого кода, вычисляется из имени файла с primary-структурой если не задано
+	output string
+	// strictTo, strictFrom запрет ручной конвертации в направлениях primary → secondary и secondary → primary
+	strictTo   bool
+	strictFrom bool
+	// fieldHooks ручная конвертация отдельных несопоставленных полей вместо всей структуры
+	fieldHooks bool
+	// fromFunc, fromMethod название функции либо метода secondary-структуры для конвертации secondary → primary
+	fromFunc   string
+	fromMethod string
+	// noTo, noFrom отключение генерации конвертаций primary → secondary и secondary → primary соответственно
+	noTo   bool
+	noFrom bool
+	// enumFallbackAll, enumFallbacks поведение конвертации перечислений для значений не имеющих пары: общее и для
+	// отдельных перечислений-приёмников по названиям их типов
+	enumFallbackAll EnumFallback
+	enumFallbacks   map[string]EnumFallback
+	// enumTo, enumFrom таблицы сопоставления констант перечислений для конвертаций primary → secondary и
+	// secondary → primary: ключи — названия констант исходного перечисления, значения — перечисления-приёмника
+	enumTo   map[string]string
+	enumFrom map[string]string
+	// enumToUsed, enumFromUsed ключи записей таблиц сопоставления констант перечислений, сопоставившие константы
+	enumToUsed   map[string]struct{}
+	enumFromUsed map[string]struct{}
+	// checkNarrowing генерация проверок сужающих приведений числовых типов
+	checkNarrowing bool
+	// collectErrors сбор ошибок конвертации всех полей вместо возврата первой из них
+	collectErrors bool
+	// errorsBackend библиотека ошибок генерируемого кода
+	errorsBackend ErrorsBackend
+	// noStubs заготовки отсутствующих функций ручной конвертации не генерируются, их отсутствие считается ошибкой
+	noStubs bool
+
+	// nested реестр конвертаций пар структур общий для всех генераторов запуска
+	nested *nestedConversions
+
+	fs *token.FileSet
+	// secFunc представление secondary-структуры в названиях функций конвертации, см. nestedConversions.secFuncName
+	secFunc string
+	// depth глубина вложенности циклов конвертации элементов слайсов и словарей в генерируемом коде
+	depth int
+	// collecting генерация конвертации значения внутри замыкания собирающего её ошибку, см. collectBegin
+	collecting bool
 }
 
 // Generate генерация кода
 func (g *Generator) Generate(prj *matiss.Project) error {
-	message.Infof("generate conversions between primary %s and secondary %s structures", g.prim, g.sec)
-
-	matches, oos := g.getFieldsMatches(nil)
-	missingPrim, missingSec := g.reportMatchingInfo(matches, oos)
-
 	// вычисляем относительный путь пакета с primary-структурой
 	pkgName := g.prim.Obj().Pkg()
 	relPkg := strings.TrimPrefix(strings.TrimPrefix(pkgName.Path(), prj.Path()), "/")
 
-	// вычисляем имя файла для генерируемой части конвертации
-	position := g.fs.Position(g.prim.Obj().Pos())
-	_, fileName := filepath.Split(position.Filename)
-	fileName = strings.TrimSuffix(fileName, ".go") + "_convgen.go"
-
 	pkg, err := prj.Package(g.prim.Obj().Pkg().Name(), relPkg)
 	if err != nil {
 		return errors.Wrap(err, "setup package of primary structure")
 	}
 
-	r, err := pkg.GoFile(fileName, matiss.GoAutogen(app.Name+" generate"))
+	r, err := pkg.GoFile(g.fileName(), matiss.GoAutogen(app.Name+" generate"))
 	if err != nil {
 		return errors.Wrap(err, "setup file to generate conversions in")
 	}
 
-	if err := g.generate(r, matches, oos, missingPrim, missingSec); err != nil {
-		return errors.Wrap(err, "generate source code")
+	missing, err := g.generateConversions(r)
+	if err != nil {
+		return err
+	}
+
+	// конвертации вложенных структур востребованные при генерации генерируются в тот же файл
+	for nested := g.nested.next(); nested != nil; nested = g.nested.next() {
+		r.N()
+		nestedMissing, err := nested.generateConversions(r)
+		if err != nil {
+			return errors.Wrapf(err, "generate conversions of nested %s and %s", nested.prim, nested.sec)
+		}
+
+		missing = append(missing, nestedMissing...)
+	}
+
+	if err := g.checkEnumTables(); err != nil {
+		return errors.Wrap(err, "check enum constants mapping")
+	}
+
+	if len(missing) > 0 && g.noStubs {
+		return errors.Newf("user defined conversions are missing:\n%s", manualHooksList(missing))
+	}
+
+	if len(missing) > 0 {
+		stubFile, err := g.manualHooksStubFile(missing)
+		if err != nil {
+			return errors.Wrap(err, "setup stubs for missing user defined conversions")
+		}
+
+		rs, err := pkg.GoFile(stubFile)
+		if err != nil {
+			return errors.Wrap(err, "setup file to generate user defined conversion stubs in")
+		}
+
+		g.generateManualHookStubs(rs, missing)
 	}
 
 	return nil
 }
 
+// generateConversions генерация конвертаций пары структур в данный файл, возвращает отсутствующие функции ручной
+// конвертации
+func (g *Generator) generateConversions(r *matiss.GoRenderer) ([]*manualHook, error) {
+	message.Infof("generate conversions between primary %s and secondary %s structures", g.prim, g.sec)
+
+	matches, oos := g.getFieldsMatches(g.manual)
+	g.reportMatchingInfo(matches, oos)
+	toFields, fromFields := g.manualFields(matches, oos)
+	var strictTo, strictFrom []*types.Var
+	if g.strictTo {
+		strictTo = toFields
+	}
+	if g.strictFrom {
+		strictFrom = fromFields
+	}
+	if len(strictTo) > 0 || len(strictFrom) > 0 {
+		return nil, g.strictError(strictTo, strictFrom)
+	}
+
+	if err := g.checkEnumFallbacks(matches, oos); err != nil {
+		return nil, err
+	}
+
+	hooks := g.getManualHooks(r, toFields, fromFields)
+	if err := g.generate(r, matches, oos, hooks); err != nil {
+		return nil, errors.Wrap(err, "generate source code")
+	}
+
+	missing, err := g.checkManualHooks(hooks)
+	if err != nil {
+		return nil, errors.Wrap(err, "check user defined conversions")
+	}
+
+	return missing, nil
+}
+
+// toName название функции конвертации primary → secondary
+func (g *Generator) toName(r *matiss.GoRenderer) string {
+	return r.S(`$0To${1|P}`, g.prim.Obj().Name(), g.secFunc)
+}
+
+// fromName название функции конвертации secondary → primary
+func (g *Generator) fromName(r *matiss.GoRenderer) string {
+	if g.fromFunc != "" {
+		return g.fromFunc
+	}
+
+	return r.S(`${0|P}To$1`, g.secFunc, g.prim.Obj().Name())
+}
+
+// checkDirections проверка согласованности названий конвертаций и отключённых направлений
+func (g *Generator) checkDirections() error {
+	if g.noTo && g.noFrom {
+		return errors.New("both conversion directions are disabled, nothing to generate")
+	}
+
+	if g.noTo && g.method != "" {
+		return errors.Newf("method %s is set for disabled primary → secondary conversion", g.method)
+	}
+
+	if g.noFrom && (g.fromFunc != "" || g.fromMethod != "") {
+		return errors.New("function or method name is set for disabled secondary → primary conversion")
+	}
+
+	if g.fromFunc != "" && g.fromMethod != "" {
+		return errors.New("function and method names of secondary → primary conversion are mutually exclusive")
+	}
+
+	if g.fromMethod != "" && g.sec.Obj().Pkg().Path() != g.prim.Obj().Pkg().Path() {
+		return errors.Newf(
+			"method %s can only be generated for secondary %s living in the package of primary %s",
+			g.fromMethod,
+			g.sec,
+			g.prim,
+		)
+	}
+
+	return nil
+}
+
+// fileName вычисление имени файла для генерируемой части конвертации
+func (g *Generator) fileName() string {
+	if g.output != "" {
+		return g.output
+	}
+
+	position := g.fs.Position(g.prim.Obj().Pos())
+	_, fileName := filepath.Split(position.Filename)
+	return strings.TrimSuffix(fileName, ".go") + "_convgen.go"
+}
+
 // generate генерация кода преобразований структур
 // TODO нужно добавить в matiss/v2 поддержку "глобальных" переменных рендерера, что-то вроде
 //      func (r *GoRenderer) Var(name string, value any)
@@ -93,25 +251,38 @@ func (g *Generator) generate(
 	r *matiss.GoRenderer,
 	matches []fieldMatchInfo,
 	oos []fieldSecondaryOneof,
-	primMismatch bool,
-	secMismatch bool,
+	hooks manualHooks,
 ) error {
-	var secname string
-	if g.sec.Obj().Pkg().Path() != g.prim.Obj().Pkg().Path() {
-		r.Imports().Add(g.sec.Obj().Pkg().Path()).Ref("secpkg")
-		secname = r.S("$secpkg.$0", g.sec.Obj().Name())
-	} else {
-		secname = g.sec.Obj().Name()
+	if !g.noTo {
+		g.generateTo(r, matches, oos, hooks)
 	}
+
+	if !g.noFrom {
+		if !g.noTo {
+			r.N()
+		}
+		g.generateFrom(r, matches, oos, hooks)
+	}
+
+	return nil
+}
+
+// generateTo генерация конвертации primary → secondary
+func (g *Generator) generateTo(
+	r *matiss.GoRenderer,
+	matches []fieldMatchInfo,
+	oos []fieldSecondaryOneof,
+	hooks manualHooks,
+) {
+	secname := g.secName(r)
 	primname := g.prim.Obj().Name()
-	secunder := strings.ReplaceAll(secname, ".", "_")
 
 	if g.method != "" {
 		r.L(`// $0 конвертация $1 в $2`, g.method, primname, secname)
 		r.L(`func (x *$0) $1() (*$2, error) {`, primname, g.method, secname)
 	} else {
-		r.L(`// $0To${1|P} конвертация $0 в $2`, primname, secunder, secname)
-		r.L(`func $0To${1|P}(x *$0) (*$2, error) {`, primname, secunder, secname)
+		r.L(`// $0 конвертация $1 в $2`, g.toName(r), primname, secname)
+		r.L(`func $0(x *$1) (*$2, error) {`, g.toName(r), primname, secname)
 	}
 
 	r.L(`    if x == nil {`)
@@ -119,6 +290,7 @@ func (g *Generator) generate(
 	r.L(`    }`)
 	r.N()
 	r.L(`    var res $0`, secname)
+	g.collectInit(r)
 
 	prim := g.prim.Underlying().(*types.Struct)
 	oopassed := map[string]struct{}{}
@@ -139,7 +311,12 @@ func (g *Generator) generate(
 				continue
 			}
 
+			if match.excludeTo {
+				continue
+			}
+
 			r.L(`// преобразование поля $0`, match.prim.Name())
+			g.collectBegin(r, g.canFail(match.descr, match.sec.Type(), match.prim.Type()))
 			g.convertValue(
 				r,
 				"res."+match.sec.Name(),
@@ -147,13 +324,12 @@ func (g *Generator) generate(
 				"x."+match.prim.Name(),
 				match.prim.Type(),
 				match.descr,
-				"field "+match.prim.Name(),
+				fieldPath(match.prim.Name(), false),
 				false,
 			)
+			g.collectEnd(r)
 
 		case oomatch != nil:
-			r.Imports().Errors().Ref("errors")
-
 			// поле соответствующее ветви oneof
 			var oofields []string
 			for _, branch := range oomatch.branches {
@@ -165,16 +341,29 @@ func (g *Generator) generate(
 				oomatch.sec.Name(),
 			)
 
+			g.collectBegin(r, true)
 			r.L(`switch {`)
 			for i, b1 := range oomatch.branches[:len(oomatch.branches)-1] {
 				for _, b2 := range oomatch.branches[i+1:] {
 					r.L(`case x.$0 != nil && x.$1 != nil:`, b1.prim.Name(), b2.prim.Name())
-					r.L(
-						`    return nil, $errors.New("fields $0 and $1 refer to respective branches of oneof $2 and must not coexist")`,
+					msg := r.S(
+						`fields $0 and $1 refer to respective branches of oneof $2 and must not coexist`,
 						b1.branch,
 						b2.branch,
 						oomatch.sec.Name(),
 					)
+					// ошибка относится к полю второй ветви, первое поле названо в причине
+					r.L(
+						`    $0`,
+						g.returnError(
+							r,
+							fieldPath(b2.prim.Name(), false),
+							g.errNew(r, msg),
+							"x."+b2.prim.Name(),
+							b2.prim.Type(),
+							oomatch.sec.Type(),
+						),
+					)
 				}
 			}
 			r.L(`}`)
@@ -192,7 +381,7 @@ func (g *Generator) generate(
 					"x."+b.prim.Name(),
 					b.prim.Type(),
 					b.descr,
-					"field "+b.prim.Name()+" into respective oneof branch",
+					fieldPath(b.prim.Name(), false),
 					true,
 				)
 				r.L(`res.$0 = &branch$1`, oomatch.sec.Name(), b.branch)
@@ -201,31 +390,50 @@ func (g *Generator) generate(
 				}
 			}
 			r.L(`}`)
+			g.collectEnd(r)
 		}
 	}
 
-	if primMismatch {
-		r.Imports().Errors().Ref("errors")
+	g.callFieldHooks(r, hooks.toFields)
 
+	if hooks.to != nil {
 		r.N()
 		r.L(`// есть несоответствие между полями, зовём ручную процедуру конвертации`)
-		r.L(`if err := manual$0To${1|P}(x, &res); err != nil {`, primname, secunder)
-		r.L(`    return nil, $errors.Wrap(err, "run user defined conversion")`)
+		r.L(`if err := $0(x, &res); err != nil {`, hooks.to.name)
+		g.fieldFailure(r, g.errWrap(r, "err", "run user defined conversion"))
 		r.L(`}`)
 	}
 
+	g.collectResult(r)
 	r.N()
 	r.L(`    return &res, nil`)
 	r.L(`}`)
+}
 
-	r.N()
-	r.L(`// ${0|P}To$1 конвертация $2 в $1`, secunder, primname, secname)
-	r.L(`func ${0|P}To$1(x *$2) (*$1, error) {`, secunder, primname, secname)
+// generateFrom генерация конвертации secondary → primary
+func (g *Generator) generateFrom(
+	r *matiss.GoRenderer,
+	matches []fieldMatchInfo,
+	oos []fieldSecondaryOneof,
+	hooks manualHooks,
+) {
+	secname := g.secName(r)
+	primname := g.prim.Obj().Name()
+
+	switch {
+	case g.fromMethod != "":
+		r.L(`// $0 конвертация $1 в $2`, g.fromMethod, secname, primname)
+		r.L(`func (x *$0) $1() (*$2, error) {`, secname, g.fromMethod, primname)
+	default:
+		r.L(`// $0 конвертация $1 в $2`, g.fromName(r), secname, primname)
+		r.L(`func $0(x *$1) (*$2, error) {`, g.fromName(r), secname, primname)
+	}
 	r.L(`    if x == nil {`)
 	r.L(`        return nil, nil`)
 	r.L(`    }`)
 	r.N()
 	r.L(`    var res $0`, primname)
+	g.collectInit(r)
 
 	sec := g.sec.Underlying().(*types.Struct)
 
@@ -242,11 +450,16 @@ func (g *Generator) generate(
 				continue
 			}
 
+			if match.excludeFrom {
+				continue
+			}
+
 			// некоторые виды descr должны быть преобразованы зеркальным образом для конвертации sec -> prim
 			descr := reflectDescr(match.descr)
 
 			r.N()
 			r.L(`// преобразование поля $0`, field.Name())
+			g.collectBegin(r, g.canFail(descr, match.prim.Type(), match.sec.Type()))
 			g.convertValue(
 				r,
 				"res."+match.prim.Name(),
@@ -254,13 +467,19 @@ func (g *Generator) generate(
 				"x."+match.sec.Name(),
 				match.sec.Type(),
 				descr,
-				"field "+match.sec.Name(),
+				fieldPath(match.sec.Name(), true),
 				false,
 			)
+			g.collectEnd(r)
 
 		case oomatch != nil:
 			r.N()
 			r.L(`// преобразование oneof-а $0`, field.Name())
+			var canFail bool
+			for _, b := range oomatch.branches {
+				canFail = canFail || g.canFail(reflectDescr(b.descr), b.prim.Type(), b.sec.Type())
+			}
+			g.collectBegin(r, canFail)
 			r.L(`switch v := x.$0.(type) {`, field.Name())
 			for _, b := range oomatch.branches {
 				r.L(`case *$0:`, g.safeBranch(r, b.branch))
@@ -271,29 +490,29 @@ func (g *Generator) generate(
 					"v."+b.branch,
 					b.sec.Type(),
 					reflectDescr(b.descr),
-					"branch "+b.branch+" of oneof "+field.Name(),
+					fieldPath(field.Name()+"."+b.branch, true),
 					false,
 				)
 			}
 			r.L(`}`)
+			g.collectEnd(r)
 		}
 	}
 
-	if secMismatch {
-		r.Imports().Errors().Ref("errors")
+	g.callFieldHooks(r, hooks.fromFields)
 
+	if hooks.from != nil {
 		r.N()
 		r.L(`// есть несоответствие между полями, зовём ручную процедуру конвертации`)
-		r.L(`if err := manual${0|P}To$1(x, &res); err != nil {`, secunder, primname)
-		r.L(`    return nil, $errors.Wrap(err, "run user defined conversion")`)
+		r.L(`if err := $0(x, &res); err != nil {`, hooks.from.name)
+		g.fieldFailure(r, g.errWrap(r, "err", "run user defined conversion"))
 		r.L(`}`)
 	}
 
+	g.collectResult(r)
 	r.N()
 	r.L(`    return &res, nil`)
 	r.L(`}`)
-
-	return nil
 }
 
 // при генерации метода primary -> secondary привязываемся к порядку полей в primary
@@ -347,7 +566,7 @@ func (g *Generator) getSecFieldConversionDiscrs(
 	// сначала ищем между соответствиями в регулярных полях
 	for _, m := range matches {
 		if m.sec == nil {
-			break
+			continue
 		}
 
 		if m.sec.Id() == secfield.Id() {
@@ -365,6 +584,16 @@ func (g *Generator) getSecFieldConversionDiscrs(
 	return nil, nil
 }
 
+// secName имя secondary-типа в генерируемом коде
+func (g *Generator) secName(r *matiss.GoRenderer) string {
+	if g.sec.Obj().Pkg().Path() == g.prim.Obj().Pkg().Path() {
+		return g.sec.Obj().Name()
+	}
+
+	ref := g.nested.importRef(r, g.sec.Obj().Pkg().Path(), "secpkg")
+	return r.S("$"+ref+".$0", g.sec.Obj().Name())
+}
+
 // typeName возвращает полное имя типа с учётом размещения в разных с primary-типом пакетах
 func (g *Generator) typeName(r *matiss.GoRenderer, x types.Type) string {
 	switch v := x.(type) {
@@ -381,9 +610,7 @@ func (g *Generator) typeName(r *matiss.GoRenderer, x types.Type) string {
 			return v.Obj().Name()
 		}
 
-		refname := fmt.Sprintf("pkgname%d", g.fqsec)
-		g.fqsec++
-		r.Imports().Add(v.Obj().Pkg().Path()).Ref(refname)
+		refname := g.nested.importRef(r, v.Obj().Pkg().Path(), "pkgname")
 		return r.S(`$`+refname+".$0", v.Obj().Name())
 
 	default:
@@ -392,15 +619,23 @@ func (g *Generator) typeName(r *matiss.GoRenderer, x types.Type) string {
 	}
 }
 
+// constName возвращает полное имя константы с учётом размещения в разных с primary-типом пакетах
+func (g *Generator) constName(r *matiss.GoRenderer, c *types.Const) string {
+	if c.Pkg().Path() == g.prim.Obj().Pkg().Path() {
+		return c.Name()
+	}
+
+	refname := g.nested.importRef(r, c.Pkg().Path(), "constpkg")
+	return r.S("$"+refname+".$0", c.Name())
+}
+
 // funcName возвращает полное имя функции преобразования с учётом размещения в разных с primary-типом пакетах
 func (g *Generator) funcName(r *matiss.GoRenderer, f *types.Func) string {
 	if f.Pkg().Path() == g.prim.Obj().Pkg().Path() {
 		return f.Name()
 	}
 
-	refname := fmt.Sprintf("fieldpkg%d", g.fqsec)
-	g.fqsec++
-	r.Imports().Add(f.Pkg().Path()).Ref(refname)
+	refname := g.nested.importRef(r, f.Pkg().Path(), "fieldpkg")
 	return r.S("$"+refname+".$0", f.Name())
 }
 
@@ -463,11 +698,19 @@ func reflectDescr(descr FieldMatchDescription) FieldMatchDescription {
 		}
 	case *FieldMatchEnum:
 		return &FieldMatchEnum{
-			Primary:   v.Secondary,
-			Secondary: v.Primary,
+			Primary:    v.Secondary,
+			Secondary:  v.Primary,
+			ByName:     v.ByName,
+			Manual:     v.Manual,
+			ProtoNames: v.ProtoNames,
+			forward:    v.backward,
+			backward:   v.forward,
 		}
 	case *FieldMatchCastable:
-		return v
+		return &FieldMatchCastable{
+			Narrowing:     v.NarrowingBack,
+			NarrowingBack: v.Narrowing,
+		}
 	case *FieldMatchSlice:
 		return &FieldMatchSlice{
 			Elem: reflectDescr(v.Elem),
@@ -477,6 +720,30 @@ func reflectDescr(descr FieldMatchDescription) FieldMatchDescription {
 			Key:  reflectDescr(v.Key),
 			Elem: reflectDescr(v.Elem),
 		}
+	case *FieldMatchNested:
+		return &FieldMatchNested{
+			Primary:   v.Secondary,
+			Secondary: v.Primary,
+			conv:      v.conv,
+			reflected: !v.reflected,
+		}
+	case *FieldMatchWellKnown:
+		return &FieldMatchWellKnown{
+			Kind:      v.Kind,
+			FromProto: !v.FromProto,
+			wk:        v.wk,
+		}
+	case *FieldMatchUUID:
+		return &FieldMatchUUID{
+			Parse:      v.Parse,
+			FromString: !v.FromString,
+			parse:      v.parse,
+		}
+	case *FieldMatchText:
+		return &FieldMatchText{
+			FromText: !v.FromText,
+			Bytes:    v.Bytes,
+		}
 	default:
 		return nil
 	}
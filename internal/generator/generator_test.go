@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerator_fromConversion(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		want   []string
+		wantNo []string
+	}{
+		{
+			name: "default",
+			want: []string{"func PointToPointPB(x *Point) (*PointPB, error)", "func PointPBToPoint(x *PointPB) (*Point, error)"},
+		},
+		{
+			name:   "from-func",
+			opts:   []Option{WithFromFunc("PointFromProto")},
+			want:   []string{"func PointToPointPB(", "func PointFromProto(x *PointPB) (*Point, error)"},
+			wantNo: []string{"func PointPBToPoint("},
+		},
+		{
+			name:   "without-from",
+			opts:   []Option{WithoutFrom()},
+			want:   []string{"func PointToPointPB("},
+			wantNo: []string{"func PointPBToPoint(", "*PointPB) (*Point, error)"},
+		},
+		{
+			name:   "without-to",
+			opts:   []Option{WithoutTo()},
+			want:   []string{"func PointPBToPoint("},
+			wantNo: []string{"func PointToPointPB("},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, _, err := generateTestdata(t, testdataPair("directions", "Point", "PointPB", tt.opts...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code := files["internal/generator/testdata/directions/directions_convgen.go"]
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code must contain %q:\n%s", want, code)
+				}
+			}
+			for _, not := range tt.wantNo {
+				if strings.Contains(code, not) {
+					t.Errorf("generated code must not contain %q:\n%s", not, code)
+				}
+			}
+		})
+	}
+}
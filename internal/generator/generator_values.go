@@ -3,14 +3,12 @@ package generator
 import (
 	"fmt"
 	"go/types"
-	"strings"
 
 	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
 )
 
-// convertValue конвертация данного значения заданного переменной src в приёмник dst
-// TODO придумать способ показывать имена ключей в ошибках. Кажется, проще всего добавлять их в контекст
-//      ошибки.
+// convertValue конвертация данного значения заданного переменной src в приёмник dst. Путь к значению whoami с
+// индексами слайсов и ключами словарей добавляется в контекст ошибок конвертации.
 func (g *Generator) convertValue(
 	r *matiss.GoRenderer,
 	dst string,
@@ -18,7 +16,7 @@ func (g *Generator) convertValue(
 	src string,
 	srcType types.Type,
 	descr FieldMatchDescription,
-	whoami string,
+	whoami valuePath,
 	noNilGuard bool,
 ) {
 	if _, ok := descr.(*FieldMatchNoMatch); ok {
@@ -41,19 +39,16 @@ func (g *Generator) convertValue(
 
 	case *FieldMatchConversion:
 		var call string
-		var sig *types.Signature
+		sig := conversionSignature(v, dstType, srcType)
 		switch {
 		case v.MethodPrimary != "":
-			sig = lookForMethod(unpointer(srcType).(*types.Named), v.MethodPrimary).Type().(*types.Signature)
 			call = r.S("$0.$1()", src, v.MethodPrimary)
 		case v.PrimaryToSecondary != "":
 			fn := lookForFunc(unpointer(srcType).(*types.Named), v.PrimaryToSecondary)
-			sig = fn.Type().(*types.Signature)
 			arg := rightReference(src, srcType, sig.Params().At(0).Type())
 			call = r.S("$0($1)", g.callName(r, fn), arg)
 		case v.SecondaryFromPrimary != "":
 			fn := lookForFunc(unpointer(dstType).(*types.Named), v.SecondaryFromPrimary)
-			sig = fn.Type().(*types.Signature)
 			arg := rightReference(src, srcType, sig.Params().At(0).Type())
 			call = r.S("$0($1)", g.callName(r, fn), arg)
 		}
@@ -62,62 +57,47 @@ func (g *Generator) convertValue(
 		case 1:
 			assignSafe(r, dst, dstType, call, sig.Results().At(0).Type(), nilGuarded)
 		case 2:
-			r.Imports().Errors().Ref("errors")
-			if nilGuarded {
-				r.L(`convres, err := $0`, call)
-				r.L(`if err != nil {`)
-				r.L(
-					`    return nil, $errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
-					whoami,
-					humanGuess(src),
-					src,
-				)
-				r.L(`}`)
-				r.N()
-				assign(r, dst, dstType, "convres", sig.Results().At(0).Type())
-			} else {
-				// вначале проверка err == nil потому что err != nil менее вероятная ситуация в данном случае
-				r.L(`if convres, err := $0; err == nil {`, call)
-				assign(r, dst, dstType, "convres", sig.Results().At(0).Type())
-				r.L(`} else {`)
-				r.L(
-					`    return nil, $errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
-					whoami,
-					humanGuess(src),
-					src,
-				)
-				r.L(`}`)
-			}
+			g.assignConverted(r, dst, dstType, src, srcType, call, sig.Results().At(0).Type(), whoami, nilGuarded)
 		}
 
-	case *FieldMatchEnum:
-		r.Imports().Errors().Ref("errors")
+	case *FieldMatchNested:
+		v.conv.nested.require(v.conv)
 
-		if v.Secondary.isProto {
-			r.L(`if enumval, ok := $0_value[int32($1)]; ok {`, g.typeName(r, dstType), deref(src, srcType))
-			assign(r, dst, dstType, "enumval", srcType)
-			r.L(`} else {`)
-			r.L(`    return $errors.Newf("unknown value %v of $0", $1)`, src, deref(src, srcType))
-			r.L(`}`)
+		resType := types.NewPointer(v.conv.sec)
+		if v.reflected {
+			resType = types.NewPointer(v.conv.prim)
+		}
+		g.assignConverted(r, dst, dstType, src, srcType, v.call(r, src, srcType), resType, whoami, nilGuarded)
+
+	case *FieldMatchWellKnown:
+		g.convertWellKnown(r, dst, dstType, src, srcType, v, whoami, nilGuarded)
+
+	case *FieldMatchUUID:
+		g.convertUUID(r, dst, dstType, src, srcType, v, whoami)
+
+	case *FieldMatchText:
+		g.convertText(r, dst, dstType, src, srcType, v, whoami)
+
+	case *FieldMatchEnum:
+		if v.ProtoNames {
+			g.convertProtoEnum(r, dst, dstType, src, srcType, v, whoami)
 		} else {
 			r.L(`switch $0 {`, deref(src, srcType))
-			for _, v := range v.Secondary.values {
-				r.L(`case $0:`, v.Val().ExactString())
-				assignSafe(
-					r,
-					dst,
-					dstType,
-					g.typeName(r, v.Type())+"("+v.Val().ExactString()+")",
-					v.Type(),
-					true,
-				)
+			for _, m := range v.forward {
+				r.L(`case $0:`, g.constName(r, m.from))
+				assignSafe(r, dst, dstType, g.constName(r, m.to), m.to.Type(), true)
 			}
 			r.L(`default:`)
-			r.L(`    return nil, $errors.Newf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
+			g.convertEnumFallback(r, dst, dstType, src, srcType, v, whoami)
 			r.L(`}`)
 		}
 
 	case *FieldMatchCastable:
+		if v.Narrowing && g.checkNarrowing {
+			g.convertNarrowing(r, dst, dstType, src, srcType, whoami)
+			break
+		}
+
 		assignSafe(
 			r,
 			dst,
@@ -134,29 +114,32 @@ func (g *Generator) convertValue(
 
 		var tmpDst string
 		if isPointer(dstType) {
-			tmpDst = "tmpslice"
+			tmpDst = g.loopVar("tmpslice")
 			r.L(`var $0 $1`, tmpDst, g.typeName(r, unpointer(dstType)))
 		} else {
 			tmpDst = dst
 		}
 
+		i, elemval := g.loopVar("i"), g.loopVar("elemval")
 		r.L(`$0 = make($1, len($2))`, tmpDst, g.typeName(r, unpointer(dstType)), deref(src, srcType))
-		r.L(`for i, elemval := range $0 {`, deref(src, srcType))
+		r.L(`for $0, $1 := range $2 {`, i, elemval, deref(src, srcType))
+		g.depth++
 		g.convertValue(
 			r,
-			tmpDst+"[i]",
+			tmpDst+"["+i+"]",
 			unpointer(dstType).(*types.Slice).Elem(),
-			"elemval",
+			elemval,
 			unpointer(srcType).(*types.Slice).Elem(),
 			v.Elem,
-			"slice element of "+whoami,
+			whoami.sliceElem(i),
 			false,
 		)
+		g.depth--
+		r.L(`}`)
 
 		if isPointer(dstType) {
 			r.L(`$0 = &$1`, dst, tmpDst)
 		}
-		r.L(`}`)
 
 		if !nilGuarded && isPointer(dstType) {
 			r.L(`}`)
@@ -169,20 +152,18 @@ func (g *Generator) convertValue(
 
 		var tmpDst string
 		if isPointer(dstType) {
-			tmpDst = "tmpmap"
+			tmpDst = g.loopVar("tmpmap")
 			r.L(`var $0 $1`, tmpDst, g.typeName(r, unpointer(dstType)))
 		} else {
 			tmpDst = dst
 		}
 
 		r.L(`$0 = make($1, len($2))`, tmpDst, g.typeName(r, unpointer(dstType)), deref(src, srcType))
-		r.L(`for keyval, elemval := range $0 {`, deref(src, srcType))
-		g.convertValue(r, tmpDst+"[keyval]", unpointer(dstType).(*types.Map).Elem(), "elemval", unpointer(srcType).(*types.Map).Elem(), v.Elem, "map element of "+whoami, false)
+		g.convertMapItems(r, tmpDst, unpointer(dstType).(*types.Map), deref(src, srcType), unpointer(srcType).(*types.Map), v, whoami)
 
 		if isPointer(dstType) {
 			r.L(`$0 = &$1`, dst, tmpDst)
 		}
-		r.L(`}`)
 
 		if !nilGuarded && isPointer(dstType) {
 			r.L(`}`)
@@ -194,14 +175,306 @@ func (g *Generator) convertValue(
 	}
 }
 
+// convertMapItems конвертация ключей и значений словаря src в словарь dst. Сконвертированные ключи могут совпасть,
+// например для сопоставленных одному значений перечислений, такие совпадения считаются ошибкой.
+func (g *Generator) convertMapItems(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType *types.Map,
+	src string,
+	srcType *types.Map,
+	descr *FieldMatchMap,
+	whoami valuePath,
+) {
+	keyval, elemval := g.loopVar("keyval"), g.loopVar("elemval")
+	convkey, convelem := g.loopVar("convkey"), g.loopVar("convelem")
+	r.L(`for $0, $1 := range $2 {`, keyval, elemval, src)
+	g.depth++
+	defer func() {
+		g.depth--
+	}()
+
+	if _, ok := descr.Key.(*FieldMatchDirect); ok && types.Identical(dstType.Key(), srcType.Key()) {
+		convkey = keyval
+	} else {
+		r.L(`var $0 $1`, convkey, g.typeName(r, dstType.Key()))
+		keypath := whoami.mapItem(keyval, srcType.Key())
+		g.convertValue(r, convkey, dstType.Key(), keyval, srcType.Key(), descr.Key, keypath, false)
+
+		// приведения без сужения не могут дать совпадающих ключей
+		if castable, ok := descr.Key.(*FieldMatchCastable); !ok || castable.Narrowing {
+			cause := g.errNewf(r, "duplicate key %v after conversion", convkey)
+			r.L(`if _, ok := $0[$1]; ok {`, dst, convkey)
+			r.L(`    $0`, g.returnError(r, keypath, cause, keyval, srcType.Key(), dstType.Key()))
+			r.L(`}`)
+		}
+	}
+
+	if _, ok := descr.Elem.(*FieldMatchDirect); ok && types.Identical(dstType.Elem(), srcType.Elem()) {
+		r.L(`$0[$1] = $2`, dst, convkey, elemval)
+		r.L(`}`)
+		return
+	}
+
+	// значения конвертируются через переменную, т.к. пустые значения не присваиваются, а ключ должен сохраниться
+	r.L(`var $0 $1`, convelem, g.typeName(r, dstType.Elem()))
+	elempath := whoami.mapItem(keyval, srcType.Key())
+	g.convertValue(r, convelem, dstType.Elem(), elemval, srcType.Elem(), descr.Elem, elempath, false)
+	r.L(`$0[$1] = $2`, dst, convkey, convelem)
+	r.L(`}`)
+}
+
+// loopVar название переменной генерируемого кода с учётом глубины вложенности циклов, чтобы переменные вложенных
+// циклов не перекрывали внешние
+func (g *Generator) loopVar(name string) string {
+	if g.depth == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s%d", name, g.depth)
+}
+
+// convertWellKnown конвертация между типом Go и соответствующим ему well-known типом протобуфа. Пустые значения
+// типа Go соответствуют nil, значения протобуфа проверяются на корректность.
+func (g *Generator) convertWellKnown(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchWellKnown,
+	whoami valuePath,
+	nilGuarded bool,
+) {
+	wk := descr.wk
+	if descr.FromProto {
+		if wk.checkValid {
+			r.L(`if err := $0.CheckValid(); err != nil {`, src)
+			r.L(`    $0`, g.returnError(r, whoami, "err", src, srcType, dstType))
+			r.L(`}`)
+			r.N()
+		}
+
+		goType := unpointer(dstType)
+		value := r.S(`$0.$1()`, src, wk.asGo)
+		if wk.wrapped != nil && is[*types.Named](goType) {
+			value = r.S(`$0($1)`, g.typeName(r, goType), value)
+		}
+
+		if wk.nonZero == "" && isPointer(dstType) {
+			// пустые значения сохраняются
+			assignAddress(r, dst, value, nilGuarded)
+			return
+		}
+
+		assignSafe(r, dst, dstType, value, goType, nilGuarded)
+		return
+	}
+
+	value := deref(src, srcType)
+	if wk.wrapped != nil && is[*types.Named](unpointer(srcType)) {
+		value = r.S(`$0($1)`, types.TypeString(wk.wrapped, nil), value)
+	}
+
+	r.Imports().Add(wk.pbPkg).Ref(wk.pbRef)
+	call := r.S(`$`+wk.pbRef+`.$0($1)`, wk.pbNew, value)
+	if isPointer(srcType) || wk.nonZero == "" {
+		assignSafe(r, dst, dstType, call, types.NewPointer(unpointer(dstType)), nilGuarded)
+		return
+	}
+
+	r.L(`if $0 {`, r.S(wk.nonZero, src))
+	assignSafe(r, dst, dstType, call, types.NewPointer(unpointer(dstType)), true)
+	r.L(`}`)
+}
+
+// convertUUID конвертация между UUID и строкой, пустая строка соответствует нулевому UUID
+func (g *Generator) convertUUID(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchUUID,
+	whoami valuePath,
+) {
+	if descr.FromString {
+		value := deref(src, srcType)
+		if is[*types.Named](unpointer(srcType)) {
+			value = "string(" + value + ")"
+		}
+
+		uuidType := descr.parse.Type().(*types.Signature).Results().At(0).Type()
+		r.L(`if $0 != "" {`, deref(src, srcType))
+		call := r.S(`$0($1)`, g.callName(r, descr.parse), value)
+		g.assignConverted(r, dst, dstType, src, srcType, call, uuidType, whoami, false)
+		r.L(`}`)
+		return
+	}
+
+	value := r.S(`$0.String()`, src)
+	if strType := unpointer(dstType); is[*types.Named](strType) {
+		value = r.S(`$0($1)`, g.typeName(r, strType), value)
+	}
+
+	r.L(`if $0 != ($1{}) {`, deref(src, srcType), g.typeName(r, unpointer(srcType)))
+	assignSafe(r, dst, dstType, value, unpointer(dstType), true)
+	r.L(`}`)
+}
+
+// convertProtoEnum конвертация между перечислением протобуфа и перечислением-строкой со значениями равными
+// названиям значений протобуфа через словари <Enum>_name и <Enum>_value пакета протобуфа
+func (g *Generator) convertProtoEnum(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchEnum,
+	whoami valuePath,
+) {
+	dstEnum := g.typeName(r, descr.Secondary.orig)
+	if descr.Secondary.isProto {
+		r.L(`if enumval, ok := $0_value[string($1)]; ok {`, dstEnum, deref(src, srcType))
+	} else {
+		r.L(`if enumval, ok := $0_name[int32($1)]; ok {`, g.typeName(r, descr.Primary.orig), deref(src, srcType))
+	}
+	assignSafe(r, dst, dstType, r.S(`$0(enumval)`, dstEnum), descr.Secondary.orig, true)
+	r.L(`} else {`)
+	g.convertEnumFallback(r, dst, dstType, src, srcType, descr, whoami)
+	r.L(`}`)
+}
+
+// convertNarrowing сужающее приведение числовых типов с проверкой того, что значение представимо в типе приёмника
+func (g *Generator) convertNarrowing(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	whoami valuePath,
+) {
+	value := deref(src, srcType)
+	srcName := g.typeName(r, unpointer(srcType))
+	dstName := g.typeName(r, unpointer(dstType))
+	sinfo, _ := numericInfo(unpointer(srcType).Underlying().(*types.Basic))
+	dinfo, _ := numericInfo(unpointer(dstType).Underlying().(*types.Basic))
+
+	var check string
+	switch {
+	case sinfo.float && dinfo.float:
+		// обратное приведение не совпадает с исходным значением при выходе за диапазон и потере точности,
+		// бесконечности сохраняются, NaN не равен самому себе и пропускается явно
+		r.Imports().Add("math").Ref("math")
+		check = r.S(`$0(narrowval) == $1 || $math.IsNaN(float64($1))`, srcName, value)
+	case !sinfo.float && !dinfo.float && sinfo.signed && !dinfo.signed:
+		check = r.S(`$0(narrowval) == $1 && $1 >= 0`, srcName, value)
+	case !sinfo.float && !dinfo.float && !sinfo.signed && dinfo.signed:
+		check = r.S(`$0(narrowval) == $1 && narrowval >= 0`, srcName, value)
+	default:
+		// обратное приведение не совпадает с исходным значением при выходе за диапазон, потере дробной части и
+		// точности, а так же для NaN
+		check = r.S(`$0(narrowval) == $1`, srcName, value)
+	}
+
+	r.L(`if narrowval := $0($1); $2 {`, dstName, value, check)
+	assignSafe(r, dst, dstType, "narrowval", unpointer(dstType), true)
+	r.L(`} else {`)
+	cause := g.errNewf(r, "value %v cannot be represented as "+dstName, value)
+	r.L(`    $0`, g.returnError(r, whoami, cause, value, srcType, dstType))
+	r.L(`}`)
+}
+
+// convertText конвертация между типом с текстовым представлением и строкой либо слайсом байтов, пустое текстовое
+// представление соответствует нулевому значению
+func (g *Generator) convertText(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchText,
+	whoami valuePath,
+) {
+	if descr.FromText {
+		text := deref(src, srcType)
+		if !descr.Bytes || is[*types.Named](unpointer(srcType)) {
+			text = "[]byte(" + text + ")"
+		}
+
+		if descr.Bytes {
+			r.L(`if len($0) > 0 {`, deref(src, srcType))
+		} else {
+			r.L(`if $0 != "" {`, deref(src, srcType))
+		}
+		r.L(`var textval $0`, g.typeName(r, unpointer(dstType)))
+		r.L(`if err := textval.UnmarshalText($0); err != nil {`, text)
+		r.L(`    $0`, g.returnError(r, whoami, "err", src, srcType, dstType))
+		r.L(`}`)
+		assign(r, dst, dstType, "textval", unpointer(dstType))
+		r.L(`}`)
+		return
+	}
+
+	value := "textval"
+	if reprType := unpointer(dstType); !descr.Bytes || is[*types.Named](reprType) {
+		value = r.S(`$0(textval)`, g.typeName(r, reprType))
+	}
+
+	r.L(`if textval, err := $0.MarshalText(); err == nil {`, src)
+	assignSafe(r, dst, dstType, value, unpointer(dstType), true)
+	r.L(`} else {`)
+	r.L(`    $0`, g.returnError(r, whoami, "err", src, srcType, dstType))
+	r.L(`}`)
+}
+
+// conversionSignature сигнатура функции либо метода конвертации
+func conversionSignature(v *FieldMatchConversion, dstType, srcType types.Type) *types.Signature {
+	switch {
+	case v.MethodPrimary != "":
+		return lookForMethod(unpointer(srcType).(*types.Named), v.MethodPrimary).Type().(*types.Signature)
+	case v.PrimaryToSecondary != "":
+		return lookForFunc(unpointer(srcType).(*types.Named), v.PrimaryToSecondary).Type().(*types.Signature)
+	default:
+		return lookForFunc(unpointer(dstType).(*types.Named), v.SecondaryFromPrimary).Type().(*types.Signature)
+	}
+}
+
+// assignConverted генерация присваивания результата вызова конвертации call возвращающей значение и ошибку
+func (g *Generator) assignConverted(
+	r *matiss.GoRenderer,
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	call string,
+	resType types.Type,
+	whoami valuePath,
+	nilGuarded bool,
+) {
+	if nilGuarded {
+		r.L(`convres, err := $0`, call)
+		r.L(`if err != nil {`)
+		r.L(`    $0`, g.returnError(r, whoami, "err", src, srcType, dstType))
+		r.L(`}`)
+		r.N()
+		assign(r, dst, dstType, "convres", resType)
+	} else {
+		// вначале проверка err == nil потому что err != nil менее вероятная ситуация в данном случае
+		r.L(`if convres, err := $0; err == nil {`, call)
+		assign(r, dst, dstType, "convres", resType)
+		r.L(`} else {`)
+		r.L(`    $0`, g.returnError(r, whoami, "err", src, srcType, dstType))
+		r.L(`}`)
+	}
+}
+
 func (g *Generator) callName(r *matiss.GoRenderer, fn *types.Func) string {
 	if g.prim.Obj().Pkg().Path() == fn.Pkg().Path() {
 		return fn.Name()
 	}
 
-	refname := fmt.Sprintf("callpkg%d", g.fqsec)
-	g.fqsec++
-	r.Imports().Add(fn.Pkg().Path()).Ref(refname)
+	refname := g.nested.importRef(r, fn.Pkg().Path(), "callpkg")
 	return r.S(`$`+refname+`.$0`, fn.Name())
 }
 
@@ -250,19 +523,24 @@ func assignSafe(
 			return
 		}
 
-		if !guarded {
-			r.L(`{`)
-		}
-		r.L(`tmp := $0`, src)
-		r.L(`$0 = &tmp`, dst)
-		if !guarded {
-			r.L(`}`)
-		}
+		assignAddress(r, dst, src, guarded)
 	default:
 		r.L(`$0 = $1`, dst, src)
 	}
 }
 
+// assignAddress генерация присваивания указателя на значение не имеющее адреса
+func assignAddress(r *matiss.GoRenderer, dst string, src string, guarded bool) {
+	if !guarded {
+		r.L(`{`)
+	}
+	r.L(`tmp := $0`, src)
+	r.L(`$0 = &tmp`, dst)
+	if !guarded {
+		r.L(`}`)
+	}
+}
+
 func lookForMethod(x *types.Named, name string) *types.Func {
 	for i := 0; i < x.NumMethods(); i++ {
 		if m := x.Method(i).Name(); m == name {
@@ -328,13 +606,3 @@ func basicZero(t types.Type) string {
 		return ""
 	}
 }
-
-// humanGuess костыль для получения названия поля из названия src
-func humanGuess(src string) string {
-	cut, after, found := strings.Cut(src, ".")
-	if !found {
-		after = cut
-	}
-
-	return matiss.Striked(after)
-}
@@ -25,7 +25,13 @@ func (g *Generator) getOrigStructs(descrs ...structDescription) (map[string]*typ
 	}
 
 	var packageNames []string
+	seen := map[string]struct{}{}
 	for _, pkg := range descrs {
+		if _, ok := seen[pkg.pkg]; ok {
+			continue
+		}
+
+		seen[pkg.pkg] = struct{}{}
 		packageNames = append(packageNames, pkg.pkg)
 	}
 
@@ -0,0 +1,398 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/message"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// manualHook описание функции ручной конвертации вызываемой из сгенерированного кода
+type manualHook struct {
+	name    string
+	params  []*types.Var
+	results []types.Type
+	// descr описание функции для комментария заготовки
+	descr string
+	// todo поля требующие ручной конвертации, по TODO-комментарию на каждое в заготовке
+	todo []string
+	// field поле заполняемое функцией, задаётся только для функций конвертации отдельных полей
+	field *types.Var
+}
+
+// manualHooks функции ручной конвертации требуемые сгенерированным кодом
+type manualHooks struct {
+	// to ручная конвертация primary → secondary
+	to *manualHook
+	// from ручная конвертация secondary → primary
+	from *manualHook
+	// toFields ручная конвертация отдельных полей secondary-структуры при конвертации primary → secondary
+	toFields []*manualHook
+	// fromFields ручная конвертация отдельных полей primary-структуры при конвертации secondary → primary
+	fromFields []*manualHook
+}
+
+// all список всех требуемых функций
+func (h manualHooks) all() []*manualHook {
+	var res []*manualHook
+	res = append(res, h.toFields...)
+	if h.to != nil {
+		res = append(res, h.to)
+	}
+	res = append(res, h.fromFields...)
+	if h.from != nil {
+		res = append(res, h.from)
+	}
+
+	return res
+}
+
+// manualFields поля требующие ручной конвертации в направлениях primary → secondary (to) и secondary → primary
+// (from), для отключённых направлений полей нет. При ручной конвертации всей структуры это несопоставленные поля
+// исходной структуры направления, при ручной конвертации отдельных полей — несопоставленные поля структуры-приёмника.
+// По этим же полям проверяется строгий режим.
+func (g *Generator) manualFields(matches []fieldMatchInfo, oos []fieldSecondaryOneof) (to, from []*types.Var) {
+	if g.fieldHooks {
+		to = g.uncoveredSecondaryFields(matches, oos)
+		for _, m := range matches {
+			if _, ok := m.descr.(*FieldMatchNoMatch); ok && !m.excludeFrom {
+				from = append(from, m.prim)
+			}
+		}
+	} else {
+		to = unmatchedPrimaryFields(matches)
+		from = g.uncoveredSecondaryFields(matches, oos)
+	}
+
+	if g.noTo {
+		to = nil
+	}
+	if g.noFrom {
+		from = nil
+	}
+
+	return to, from
+}
+
+// getManualHooks вычисление функций ручной конвертации требуемых для данных результатов сопоставления полей
+func (g *Generator) getManualHooks(r *matiss.GoRenderer, toFields, fromFields []*types.Var) manualHooks {
+	primname := g.prim.Obj().Name()
+	secunder := g.secFunc
+	primptr := types.NewPointer(g.prim)
+	secptr := types.NewPointer(g.sec)
+	errType := types.Universe.Lookup("error").Type()
+
+	// в комментариях типы из других пакетов квалифицируются названиями пакетов
+	qualifier := func(pkg *types.Package) string {
+		if pkg == g.prim.Obj().Pkg() {
+			return ""
+		}
+
+		return pkg.Name()
+	}
+	primdescr := types.TypeString(g.prim, qualifier)
+	secdescr := types.TypeString(g.sec, qualifier)
+
+	structHook := func(name string, src, dst types.Type, descr string, todo []string) *manualHook {
+		return &manualHook{
+			name: name,
+			params: []*types.Var{
+				types.NewVar(token.NoPos, nil, "x", src),
+				types.NewVar(token.NoPos, nil, "res", dst),
+			},
+			results: []types.Type{errType},
+			descr:   descr,
+			todo:    todo,
+		}
+	}
+	fieldTodo := func(f *types.Var, side string) string {
+		return r.S(`поле $0 ($1) $2-структуры не сопоставлено`, f.Name(), types.TypeString(f.Type(), qualifier), side)
+	}
+
+	var res manualHooks
+	if !g.fieldHooks {
+		// в заготовке каждого направления перечисляются несопоставленные поля исходной структуры этого направления
+		if len(toFields) > 0 {
+			var todo []string
+			for _, f := range toFields {
+				todo = append(todo, fieldTodo(f, "primary"))
+			}
+
+			res.to = structHook(
+				r.S(`manual$0To${1|P}`, primname, secunder),
+				primptr,
+				secptr,
+				r.S(`ручная конвертация полей $0 в $1 не сопоставленных автоматически`, primdescr, secdescr),
+				todo,
+			)
+		}
+
+		if len(fromFields) > 0 {
+			var todo []string
+			for _, f := range fromFields {
+				todo = append(todo, fieldTodo(f, "secondary"))
+			}
+
+			res.from = structHook(
+				r.S(`manual${0|P}To$1`, secunder, primname),
+				secptr,
+				primptr,
+				r.S(`ручная конвертация полей $0 в $1 не сопоставленных автоматически`, secdescr, primdescr),
+				todo,
+			)
+		}
+
+		return res
+	}
+
+	// ручная конвертация отдельных полей: функции генерируются для несопоставленных полей структуры-приёмника,
+	// поля типы которых не могут быть использованы в пакете primary-структуры остаются на ручную конвертацию
+	// всей структуры
+	fieldHook := func(name string, src types.Type, f *types.Var, descr string) *manualHook {
+		return &manualHook{
+			name: name,
+			params: []*types.Var{
+				types.NewVar(token.NoPos, nil, "x", src),
+			},
+			results: []types.Type{f.Type(), errType},
+			descr:   descr,
+			todo:    []string{r.S(`вычислить значение поля $0`, f.Name())},
+			field:   f,
+		}
+	}
+
+	var toTodo []string
+	for _, f := range toFields {
+		if !g.isReferable(f.Type()) {
+			toTodo = append(toTodo, fieldTodo(f, "secondary"))
+			continue
+		}
+
+		res.toFields = append(res.toFields, fieldHook(
+			r.S(`convert$0To${1|P}$2`, primname, secunder, f.Name()),
+			primptr,
+			f,
+			r.S(`ручная конвертация $0 в поле $1 структуры $2`, primdescr, f.Name(), secdescr),
+		))
+	}
+	if len(toTodo) > 0 {
+		res.to = structHook(
+			r.S(`manual$0To${1|P}`, primname, secunder),
+			primptr,
+			secptr,
+			r.S(`ручная конвертация полей $0 в $1 не сопоставленных автоматически`, primdescr, secdescr),
+			toTodo,
+		)
+	}
+
+	for _, f := range fromFields {
+		res.fromFields = append(res.fromFields, fieldHook(
+			r.S(`convert${0|P}To$1$2`, secunder, primname, f.Name()),
+			secptr,
+			f,
+			r.S(`ручная конвертация $0 в поле $1 структуры $2`, secdescr, f.Name(), primdescr),
+		))
+	}
+
+	return res
+}
+
+// isReferable проверка, что на данный тип можно сослаться из пакета primary-структуры
+func (g *Generator) isReferable(t types.Type) bool {
+	switch v := t.(type) {
+	case *types.Basic:
+		return true
+	case *types.Pointer:
+		return g.isReferable(v.Elem())
+	case *types.Slice:
+		return g.isReferable(v.Elem())
+	case *types.Map:
+		return g.isReferable(v.Key()) && g.isReferable(v.Elem())
+	case *types.Named:
+		return v.Obj().Exported() || v.Obj().Pkg() == g.prim.Obj().Pkg()
+	default:
+		return false
+	}
+}
+
+// checkManualHooks проверка сигнатур существующих функций ручной конвертации, возвращает отсутствующие
+func (g *Generator) checkManualHooks(hooks manualHooks) ([]*manualHook, error) {
+	scope := g.prim.Obj().Pkg().Scope()
+
+	var missing []*manualHook
+	for _, hook := range hooks.all() {
+		obj := scope.Lookup(hook.name)
+		if obj == nil {
+			missing = append(missing, hook)
+			continue
+		}
+
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			return nil, errors.Newf("%s %s must be a function", g.fs.Position(obj.Pos()), hook.name)
+		}
+
+		if !hook.matches(fn.Type().(*types.Signature)) {
+			return nil, errors.Newf(
+				"%s %s has signature %s, must be %s",
+				g.fs.Position(obj.Pos()),
+				hook.name,
+				fn.Type(),
+				hook.signature(),
+			)
+		}
+	}
+
+	return missing, nil
+}
+
+// manualHooksStubFile имя файла для заготовок отсутствующих функций ручной конвертации. Существующие файлы не
+// перезаписываются: если файл заготовок уже есть, то используется первое свободное имя с номером, например
+// user_convmanual2.go.
+func (g *Generator) manualHooksStubFile(missing []*manualHook) (string, error) {
+	base := g.fileName()
+	if strings.HasSuffix(base, "_convgen.go") {
+		base = strings.TrimSuffix(base, "_convgen.go") + "_convmanual"
+	} else {
+		base = strings.TrimSuffix(base, ".go") + "_manual"
+	}
+
+	position := g.fs.Position(g.prim.Obj().Pos())
+	dir := filepath.Dir(position.Filename)
+	name := base + ".go"
+	for i := 2; ; i++ {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "check stubs file "+name)
+		}
+
+		name = base + strconv.Itoa(i) + ".go"
+	}
+
+	for _, hook := range missing {
+		message.Warningf("generate stub for user defined conversion %s in %s", hook.name, filepath.Join(dir, name))
+	}
+
+	return name, nil
+}
+
+// manualHooksList список сигнатур функций ручной конвертации для сообщений об ошибках
+func manualHooksList(hooks []*manualHook) string {
+	var sigs []string
+	for _, hook := range hooks {
+		sigs = append(sigs, "    func "+hook.name+strings.TrimPrefix(hook.signature(), "func"))
+	}
+
+	return strings.Join(sigs, "\n")
+}
+
+// generateManualHookStubs генерация заготовок функций ручной конвертации
+func (g *Generator) generateManualHookStubs(r *matiss.GoRenderer, hooks []*manualHook) {
+	for i, hook := range hooks {
+		g.generateManualHookStub(r, hook)
+		if i < len(hooks)-1 {
+			r.N()
+		}
+	}
+}
+
+// generateManualHookStub генерация заготовки функции ручной конвертации
+func (g *Generator) generateManualHookStub(r *matiss.GoRenderer, hook *manualHook) {
+	var params []string
+	for _, p := range hook.params {
+		params = append(params, p.Name()+" "+g.hookTypeName(r, p.Type()))
+	}
+
+	var results []string
+	for _, t := range hook.results {
+		results = append(results, g.hookTypeName(r, t))
+	}
+
+	r.L(`// $0 $1`, hook.name, hook.descr)
+	if len(results) == 1 {
+		r.L(`func $0($1) $2 {`, hook.name, strings.Join(params, ", "), results[0])
+	} else {
+		r.L(`func $0($1) ($2) {`, hook.name, strings.Join(params, ", "), strings.Join(results, ", "))
+	}
+	for _, todo := range hook.todo {
+		r.L(`    // TODO $0`, todo)
+	}
+
+	if len(results) == 1 {
+		r.L(`    return $0`, g.errNew(r, hook.name+" is not implemented"))
+	} else {
+		r.L(`    var res $0`, results[0])
+		r.L(`    return res, $0`, g.errNew(r, hook.name+" is not implemented"))
+	}
+	r.L(`}`)
+}
+
+// callFieldHooks генерация вызовов функций ручной конвертации отдельных полей
+func (g *Generator) callFieldHooks(r *matiss.GoRenderer, hooks []*manualHook) {
+	for _, hook := range hooks {
+		r.N()
+		// у каждого поля своя переменная с результатом, т.к. все вызовы находятся в одной области видимости
+		convres := "conv" + hook.field.Name()
+		r.L(`// ручная конвертация поля $0`, hook.field.Name())
+		r.L(`$0, err := $1(x)`, convres, hook.name)
+		r.L(`if err != nil {`)
+		g.fieldFailure(r, g.errWrap(r, "err", "run user defined conversion of field "+hook.field.Name()))
+		r.L(`}`)
+		r.L(`res.$0 = $1`, hook.field.Name(), convres)
+	}
+}
+
+// hookTypeName имя типа для сигнатуры функции ручной конвертации
+func (g *Generator) hookTypeName(r *matiss.GoRenderer, t types.Type) string {
+	if types.Identical(t, types.Universe.Lookup("error").Type()) {
+		return "error"
+	}
+
+	return g.typeName(r, t)
+}
+
+// matches проверка соответствия сигнатуры существующей функции ожидаемой
+func (h *manualHook) matches(sig *types.Signature) bool {
+	if sig.Recv() != nil || sig.Variadic() {
+		return false
+	}
+
+	if sig.Params().Len() != len(h.params) || sig.Results().Len() != len(h.results) {
+		return false
+	}
+
+	for i, p := range h.params {
+		if !types.Identical(sig.Params().At(i).Type(), p.Type()) {
+			return false
+		}
+	}
+
+	for i, t := range h.results {
+		if !types.Identical(sig.Results().At(i).Type(), t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// signature ожидаемая сигнатура функции
+func (h *manualHook) signature() string {
+	var results []*types.Var
+	for _, t := range h.results {
+		results = append(results, types.NewVar(token.NoPos, nil, "", t))
+	}
+
+	sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(h.params...), types.NewTuple(results...), false)
+	return types.TypeString(sig, nil)
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func Test_manualHook_matches(t *testing.T) {
+	domain := types.NewPackage("example.com/domain", "domain")
+	prim := newTestNamed(domain, "Region", types.NewStruct(nil, nil))
+	sec := newTestNamed(types.NewPackage("example.com/pb", "pb"), "Region", types.NewStruct(nil, nil))
+	errType := types.Universe.Lookup("error").Type()
+
+	param := func(t types.Type) *types.Var {
+		return types.NewParam(token.NoPos, domain, "", t)
+	}
+	signature := func(recv *types.Var, params []types.Type, results []types.Type, variadic bool) *types.Signature {
+		var ps, rs []*types.Var
+		for _, p := range params {
+			ps = append(ps, param(p))
+		}
+		for _, r := range results {
+			rs = append(rs, param(r))
+		}
+		return types.NewSignatureType(recv, nil, nil, types.NewTuple(ps...), types.NewTuple(rs...), variadic)
+	}
+
+	hook := &manualHook{
+		name:    "manualRegionToPbRegion",
+		params:  []*types.Var{param(types.NewPointer(prim)), param(types.NewPointer(sec))},
+		results: []types.Type{errType},
+	}
+
+	tests := []struct {
+		name string
+		sig  *types.Signature
+		want bool
+	}{
+		{
+			name: "same",
+			sig:  signature(nil, []types.Type{types.NewPointer(prim), types.NewPointer(sec)}, []types.Type{errType}, false),
+			want: true,
+		},
+		{
+			name: "value-param",
+			sig:  signature(nil, []types.Type{types.NewPointer(prim), sec}, []types.Type{errType}, false),
+		},
+		{
+			name: "swapped-params",
+			sig:  signature(nil, []types.Type{types.NewPointer(sec), types.NewPointer(prim)}, []types.Type{errType}, false),
+		},
+		{
+			name: "no-result",
+			sig:  signature(nil, []types.Type{types.NewPointer(prim), types.NewPointer(sec)}, nil, false),
+		},
+		{
+			name: "method",
+			sig: signature(
+				param(types.NewPointer(prim)),
+				[]types.Type{types.NewPointer(prim), types.NewPointer(sec)},
+				[]types.Type{errType},
+				false,
+			),
+		},
+		{
+			name: "variadic",
+			sig: signature(
+				nil,
+				[]types.Type{types.NewPointer(prim), types.NewSlice(types.NewPointer(sec))},
+				[]types.Type{errType},
+				true,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hook.matches(tt.sig); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_fieldHooksGenerated(t *testing.T) {
+	files := runGenerated(t, "fieldhooks", testdataPair("fieldhooks", "Order", "OrderPB", WithFieldHooks()))
+	for path, content := range files {
+		if strings.Contains(path, "_convmanual") {
+			t.Errorf("existing field hooks must not be stubbed, got %s:\n%s", path, content)
+		}
+	}
+}
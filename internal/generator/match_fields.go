@@ -2,6 +2,7 @@ package generator
 
 import (
 	"go/types"
+	"sort"
 
 	"github.com/sirkon/message"
 
@@ -14,6 +15,12 @@ type fieldMatchInfo struct {
 	prim  *types.Var
 	sec   *types.Var
 	descr FieldMatchDescription
+	// manual сопоставление задано вручную
+	manual bool
+	// excludeTo поле исключено тегом из конвертации primary → secondary
+	excludeTo bool
+	// excludeFrom поле исключено тегом из конвертации secondary → primary
+	excludeFrom bool
 }
 
 // fieldSecondaryOneof тип сопоставляющий полю oneof-а из secondary-типа поля из primary-типа
@@ -39,7 +46,11 @@ type fieldBranchDescr struct {
 // getFieldsMatches поиск эквивалентных полей.
 // Критерий эквивалентности полей, должны выполняться оба условия:
 //     • Совпадают значения полученные из имён полей с помощью matiss.Underscored либо вручную задано сопоставление
-//        одного поля другому в словаре manual (сейчас не заполняется)
+//        одного поля другому в словаре manual (задаётся опцией WithManualMatches). Поля secondary-структуры
+//        сопоставленные вручную не сопоставляются по имени никаким другим полям.
+//     • Сопоставление может быть задано тегом conv поля primary-структуры (см. convTag), ручное сопоставление
+//       имеет над ним приоритет. Этим же тегом поле может быть исключено из конвертации целиком или в одном из
+//       направлений, полностью исключённые поля на эквивалентность типов не проверяются.
 //     • Сопоставленные по имени поля имеют эквивалентные типы.
 // Критерий эквивалентности типа:
 //   Типы полей U и V являются эквивалентными (U ~ V) если выполняется одно из следующих условий (в порядке уменьшения
@@ -48,20 +59,25 @@ type fieldBranchDescr struct {
 //     • X ~ *X
 //     • В пакете с типом U или V доступны ПУБЛИЧНЫЕ функции и/или методы преобразования из типа X в тип Y и обратно,
 //       где X ~ U и Y ~ V. Данные функции методы не должны принимать никаких аргументов и возвращать либо результат
-//       типа (*)V, или ((*)V, error).
+//       типа (*)V, или ((*)V, error). Здесь учитываются только функции из пакетов конвертируемых структур.
+//     • Встроенные сопоставления: well-known типы и обёртки протобуфа (matchWellKnown), UUID и строки (matchUUID),
+//       типы с текстовым представлением и строки либо слайсы байтов (matchText).
+//     • То же, что и в пункте о функциях преобразования, для функций из остальных пакетов.
 //     • Типы U и V:
 //         • Являются перечислениями в смысле Go (определяются функцией getEnumInfo)
-//         • Значения перечислений совпадают либо, в случае перечислений-строк, значения типа U являются суффиксами
-//           значений V или наоборот. Например, пара DBRegionID и RegionId удовлетворяют этому значению.
-//         • TODO сформулировать критерий совпадения по именам значений — это чуть сложнее, должны до какой-то степени
-//                совпадать суффиксы.
+//         • Имена констант перечислений без префиксов с именами типов совпадают, например REGION_KIND_PRIMARY и
+//           RegionKindPrimary, либо значения перечислений совпадают, либо, в случае перечислений-строк, значения
+//           типа U являются суффиксами значений V или наоборот. Например, пара DBRegionID и RegionId удовлетворяют
+//           этому значению. Так же значения могут быть сопоставлены вручную таблицами (WithEnumMapping).
+//           Подробнее см. matchEnums.
 //         • Warning: если типы оба являются перечислениями но не выполняются критерии из этого подпункта, то
 //                    они НЕ являются эквивалентными.
 //     • X и Y приводятся друг к другу и X ~ U, Y ~ V
 //     • []X ~ []Y если X ~ Y
 //     • map[A]B ~ map[X]Y если A ~ X и B ~ Y
 //   Warning: целочисленные типы различных размерностей, например int8 и uin64, считаются эквивалентными в рамках
-//            данных критериев.
+//            данных критериев. Сужающие приведения помечаются в описании сопоставления и могут проверяться при
+//            конвертации (WithNarrowingChecks).
 //
 // Кроме этого, заводится специальный костыль для полей соответсвующих oneof для структур сгенерированных
 // protoc-gen-go. Такие поля ищутся только в secondary-типе следующим образом:
@@ -73,13 +89,40 @@ type fieldBranchDescr struct {
 //   5. Если тип только найденного поля эквивалентен типу поля в ветви, то считается что найдено соответствие между
 //      ветвью и полем в primary-типе
 //   6. Если для всех ветвей было найдено соответствие в полях, то такие поля удаляются из поматченных
-//
-// TODO реализовать наполнение словаря manual для ручного указания эквивалентных полей
 func (g *Generator) getFieldsMatches(manual map[string]string) ([]fieldMatchInfo, []fieldSecondaryOneof) {
 	prim := g.prim.Underlying().(*types.Struct)
 	sec := g.sec.Underlying().(*types.Struct)
 
 	var errorsHappened bool
+
+	// разбираем теги, поля secondary-структуры сопоставленные вручную либо тегами не должны сопоставляться
+	// по имени никаким другим полям
+	tags := make([]convTag, prim.NumFields())
+	targets := map[string]struct{}{}
+	for _, name := range manual {
+		targets[name] = struct{}{}
+	}
+	for i := 0; i < prim.NumFields(); i++ {
+		tag, err := parseConvTag(prim.Tag(i))
+		if err != nil {
+			message.Errorf("%s invalid %s tag: %s", g.fs.Position(prim.Field(i).Pos()), convTagName, err)
+			errorsHappened = true
+			continue
+		}
+
+		if _, ok := g.ignored[matiss.Underscored(prim.Field(i).Name())]; ok {
+			tag = convTag{
+				excludeTo:   true,
+				excludeFrom: true,
+			}
+		}
+
+		tags[i] = tag
+		if tag.name != "" {
+			targets[matiss.Underscored(tag.name)] = struct{}{}
+		}
+	}
+
 	var res []fieldMatchInfo
 outer:
 	for i := 0; i < prim.NumFields(); i++ {
@@ -88,39 +131,75 @@ outer:
 			continue
 		}
 
-		if err := checkTypeSupport(pf.Type()); err != nil {
-			message.Errorf("%s %s", g.fs.Position(pf.Pos()), err)
-			errorsHappened = true
+		tag := tags[i]
+		excluded := tag.excludeTo && tag.excludeFrom
+		if !excluded {
+			// полностью исключённые поля могут иметь и неподдерживаемые типы
+			if err := checkTypeSupport(pf.Type()); err != nil {
+				message.Errorf("%s %s", g.fs.Position(pf.Pos()), err)
+				errorsHappened = true
+			}
 		}
 
+		// ручное сопоставление имеет приоритет над тегом, который в свою очередь приоритетнее совпадения имён
 		want := matiss.Underscored(pf.Name())
-		if name, ok := manual[want]; ok {
+		name, isManual := manual[want]
+		switch {
+		case isManual:
 			want = name
+		case tag.name != "":
+			want = matiss.Underscored(tag.name)
 		}
+		isTargeted := isManual || tag.name != ""
 
 		for j := 0; j < sec.NumFields(); j++ {
 			ps := sec.Field(j)
-			if matiss.Underscored(ps.Name()) != want {
+			secName := matiss.Underscored(ps.Name())
+			if secName != want {
 				continue
 			}
 
-			eq := g.getTypeMatchDescription(pf.Type(), ps.Type())
+			if _, ok := targets[secName]; ok && !isTargeted {
+				// это поле уже вручную сопоставлено другому
+				continue
+			}
+
+			var eq FieldMatchDescription = &FieldMatchNoMatch{}
+			if !excluded {
+				eq = g.getTypeMatchDescription(pf.Type(), ps.Type())
+			}
 			res = append(res, fieldMatchInfo{
-				prim:  pf,
-				sec:   ps,
-				descr: eq,
+				prim:        pf,
+				sec:         ps,
+				descr:       eq,
+				manual:      isManual,
+				excludeTo:   tag.excludeTo,
+				excludeFrom: tag.excludeFrom,
 			})
 			continue outer
 		}
 
+		if tag.name != "" && !isManual {
+			message.Errorf(
+				"%s %s tag refers to field %s which is missing in secondary structure %s",
+				g.fs.Position(pf.Pos()),
+				convTagName,
+				tag.name,
+				g.sec,
+			)
+			errorsHappened = true
+		}
+
 		res = append(res, fieldMatchInfo{
-			prim:  pf,
-			descr: &FieldMatchNoMatch{},
+			prim:        pf,
+			descr:       &FieldMatchNoMatch{},
+			excludeTo:   tag.excludeTo,
+			excludeFrom: tag.excludeFrom,
 		})
 	}
 
 	if errorsHappened {
-		message.Fatal("unhandled types met, cannot continue")
+		message.Fatal("unhandled types or invalid tags met, cannot continue")
 	}
 
 	res, oneofs := g.matchOneofs(sec, res)
@@ -128,6 +207,86 @@ outer:
 	return res, oneofs
 }
 
+// checkManualMatches проверка, что вручную сопоставленные и исключённые поля существуют в primary и secondary
+// структурах, а вручную сопоставленные поля не повторяются
+func (g *Generator) checkManualMatches() error {
+	prim := g.prim.Underlying().(*types.Struct)
+	sec := g.sec.Underlying().(*types.Struct)
+
+	// ключи проверяются в фиксированном порядке, чтобы при нескольких ошибках сообщение не менялось от запуска к
+	// запуску. В сообщениях поля называются так, как они были заданы.
+	primNames := make([]string, 0, len(g.manualInput))
+	for primName := range g.manualInput {
+		primNames = append(primNames, primName)
+	}
+	sort.Strings(primNames)
+
+	primSeen := map[string]string{}
+	secSeen := map[string]string{}
+	for _, primName := range primNames {
+		secName := g.manualInput[primName]
+		if lookupFieldByName(prim, matiss.Underscored(primName)) == nil {
+			return errors.Newf("primary structure %s has no field %s", g.prim, primName)
+		}
+
+		if lookupFieldByName(sec, matiss.Underscored(secName)) == nil {
+			return errors.Newf("secondary structure %s has no field %s", g.sec, secName)
+		}
+
+		if prev, ok := primSeen[matiss.Underscored(primName)]; ok {
+			return errors.Newf(
+				"manual matches %s=%s and %s=%s refer to the same primary field",
+				prev,
+				g.manualInput[prev],
+				primName,
+				secName,
+			)
+		}
+		primSeen[matiss.Underscored(primName)] = primName
+
+		if prev, ok := secSeen[matiss.Underscored(secName)]; ok {
+			return errors.Newf(
+				"manual matches %s=%s and %s=%s refer to the same secondary field",
+				prev,
+				g.manualInput[prev],
+				primName,
+				secName,
+			)
+		}
+		secSeen[matiss.Underscored(secName)] = primName
+	}
+
+	ignored := make([]string, 0, len(g.ignored))
+	for name := range g.ignored {
+		ignored = append(ignored, name)
+	}
+	sort.Strings(ignored)
+
+	for _, name := range ignored {
+		if lookupFieldByName(prim, name) == nil && lookupFieldByName(sec, name) == nil {
+			return errors.Newf("ignored field %s is missing in both %s and %s", g.ignored[name], g.prim, g.sec)
+		}
+	}
+
+	return nil
+}
+
+// lookupFieldByName поиск публичного поля структуры по имени приведённому к виду matiss.Underscored
+func lookupFieldByName(s *types.Struct, name string) *types.Var {
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		if matiss.Underscored(f.Name()) == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
 func (g *Generator) matchOneofs(sec *types.Struct, res []fieldMatchInfo) ([]fieldMatchInfo, []fieldSecondaryOneof) {
 	// ищем oneof-поля
 	var oneofs []fieldSecondaryOneof
@@ -202,6 +361,10 @@ oouter:
 						continue
 					}
 
+					if m.excludeTo || m.excludeFrom {
+						continue
+					}
+
 					if matiss.Underscored(m.prim.Name()) != matiss.Underscored(f.Name()) {
 						continue
 					}
@@ -265,36 +428,48 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 		return g.getTypeMatchDescription(prim, v.Elem())
 	}
 
+	// функции преобразования объявленные в пакетах конвертируемых структур задаются явно и имеют приоритет над
+	// встроенными сопоставлениями
+	if v, ok := g.conversion(prim, sec, true); ok {
+		return v
+	}
+
+	// well-known типы протобуфа и соответствующие им типы Go
+	if v, ok := matchWellKnown(prim, sec); ok {
+		return v
+	}
+
+	// UUID и строки, до поиска функций преобразования в остальных пакетах чтобы не использовать паникующие функции
+	// вроде uuid.MustParse
+	if v, ok := matchUUID(prim, sec); ok {
+		return v
+	}
+
+	// типы с текстовым представлением и строки либо слайсы байтов, так же до поиска функций преобразования: методы
+	// вида func (T) Zone() string не являются преобразованием
+	if v, ok := matchText(prim, sec); ok {
+		return v
+	}
+
 	// если имеются функции преобразования между типами
-	if v, ok := g.thereIsConversion(prim, sec); ok {
+	if v, ok := g.conversion(prim, sec, false); ok {
 		return v
 	}
 
-	// функции преобразования могут быть из secondary в primary
-	if v, ok := g.thereIsConversion(sec, prim); ok {
-		// меняем местами направление методов
-		return &FieldMatchConversion{
-			MethodPrimary:        "",
-			PrimaryToSecondary:   "",
-			PrimaryFromSecondary: "",
-			MethodSecondary:      v.MethodPrimary,
-			SecondaryToPrimary:   v.PrimaryToSecondary,
-			SecondaryFromPrimary: v.PrimaryFromSecondary,
-		}
+	// вложенные структуры, конвертации между которыми генерируются в этом же запуске
+	if v, ok := g.nestedMatch(prim, sec); ok {
+		return v
 	}
 
 	// если подозрительно похожие енумии
-	penum, senum, enummatch := g.matchEnums(prim, sec)
+	enumMatchDescr, enummatch := g.matchEnums(prim, sec)
 	switch enummatch {
 	case enumMatchStateNotEnums:
 		// оба не енумии, продолжаем проверку дальше
 	case enumMatchStateOneIsNotEnum, enumMatchStateDifferentEnums:
 		return &FieldMatchNoMatch{}
 	case enumMatchStateMatched:
-		return &FieldMatchEnum{
-			Primary:   penum,
-			Secondary: senum,
-		}
+		return enumMatchDescr
 	}
 
 	// типы могут приводиться друг к другу
@@ -335,7 +510,7 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 	}
 
 	if basicAssignable(prim, sec) {
-		return &FieldMatchCastable{}
+		return newFieldMatchCastable(prim, sec)
 	}
 
 	return &FieldMatchNoMatch{}
@@ -423,18 +598,37 @@ func canBeEnum(t types.Type) bool {
 	return false
 }
 
-func (g *Generator) reportMatchingInfo(
-	m []fieldMatchInfo,
-	oos []fieldSecondaryOneof,
-) (missingPrimary bool, missingSecondary bool) {
+func (g *Generator) reportMatchingInfo(m []fieldMatchInfo, oos []fieldSecondaryOneof) {
 	message.Info("\nregular fields matches")
 
 	for _, info := range m {
-		if _, ok := info.descr.(*FieldMatchNoMatch); ok {
-			missingPrimary = true
-		}
-
-		if info.sec != nil {
+		switch {
+		case info.excludeTo && info.excludeFrom:
+			message.Infof("primary field %s (%s): excluded", info.prim.Name(), info.prim.Type())
+		case info.excludeTo:
+			message.Infof(
+				"primary field %s (%s): excluded from primary → secondary conversion, %s",
+				info.prim.Name(),
+				info.prim.Type(),
+				info.descr,
+			)
+		case info.excludeFrom:
+			message.Infof(
+				"primary field %s (%s): excluded from secondary → primary conversion, %s",
+				info.prim.Name(),
+				info.prim.Type(),
+				info.descr,
+			)
+		case info.sec != nil && info.manual:
+			message.Infof(
+				"primary %s(%s) ↔ secondary %s(%s): %s (manual mapping)",
+				info.prim.Name(),
+				info.prim.Type(),
+				info.sec.Name(),
+				info.sec.Type(),
+				info.descr,
+			)
+		case info.sec != nil:
 			message.Infof(
 				"primary %s(%s) ↔ secondary %s(%s): %s",
 				info.prim.Name(),
@@ -443,7 +637,7 @@ func (g *Generator) reportMatchingInfo(
 				info.sec.Type(),
 				info.descr,
 			)
-		} else {
+		default:
 			message.Warningf("primary field %s (%s): %s", info.prim.Name(), info.prim.Type(), info.descr)
 		}
 	}
@@ -465,26 +659,37 @@ func (g *Generator) reportMatchingInfo(
 		}
 	}
 
-	missingSecondary = g.secondaryHasUncoveredFields(m, oos)
-
 	message.Info()
 
-	if missingPrimary {
+	if len(unmatchedPrimaryFields(m)) > 0 {
 		message.Warning("not all primary fields were matched")
 	}
 
-	if missingSecondary {
+	if len(g.uncoveredSecondaryFields(m, oos)) > 0 {
 		message.Warning("not all secondary fields were matched")
 	}
+}
 
-	return missingPrimary, missingSecondary
+// unmatchedPrimaryFields поля primary-типа для которых не найдено соответствие и которые не исключены из
+// конвертации primary → secondary
+func unmatchedPrimaryFields(ms []fieldMatchInfo) []*types.Var {
+	var res []*types.Var
+	for _, m := range ms {
+		if _, ok := m.descr.(*FieldMatchNoMatch); ok && !m.excludeTo {
+			res = append(res, m.prim)
+		}
+	}
+
+	return res
 }
 
-// secondaryHasUncoveredFields выяснение, что имеются публичные поля в secondary-типе для которых не найдено
-// соответствие в primary.
-func (g *Generator) secondaryHasUncoveredFields(ms []fieldMatchInfo, oos []fieldSecondaryOneof) bool {
+// uncoveredSecondaryFields поиск публичных полей в secondary-типе для которых не найдено соответствие в primary.
+// Поле сопоставленное по имени полю с неэквивалентным типом считается не покрытым, если только соответствующее
+// поле primary-типа не исключено из конвертации secondary → primary.
+func (g *Generator) uncoveredSecondaryFields(ms []fieldMatchInfo, oos []fieldSecondaryOneof) []*types.Var {
 	sec := g.sec.Underlying().(*types.Struct)
 
+	var res []*types.Var
 outer:
 	for i := 0; i < sec.NumFields(); i++ {
 		f := sec.Field(i)
@@ -492,14 +697,20 @@ outer:
 			continue
 		}
 
+		if _, ok := g.ignored[matiss.Underscored(f.Name())]; ok {
+			continue
+		}
+
 		for _, m := range ms {
-			if m.sec == nil {
-				continue outer
+			if m.sec == nil || m.sec.Id() != f.Id() {
+				continue
 			}
 
-			if m.sec.Id() == f.Id() {
-				continue outer
+			if _, ok := m.descr.(*FieldMatchNoMatch); ok && !m.excludeFrom {
+				break
 			}
+
+			continue outer
 		}
 
 		for _, oo := range oos {
@@ -508,10 +719,10 @@ outer:
 			}
 		}
 
-		return true
+		res = append(res, f)
 	}
 
-	return false
+	return res
 }
 
 // checkTypeSupport не все типы разрешены
@@ -2,6 +2,41 @@ package generator
 
 import "go/types"
 
+// conversion поиск функций преобразования между типами prim и sec в пакете каждого из них. С declared функции
+// ищутся только в пакетах конвертируемых структур.
+func (g *Generator) conversion(prim, sec types.Type, declared bool) (*FieldMatchConversion, bool) {
+	if !declared || g.inPairPackage(prim) {
+		if v, ok := g.thereIsConversion(prim, sec); ok {
+			return v, true
+		}
+	}
+
+	// функции преобразования могут быть из secondary в primary
+	if !declared || g.inPairPackage(sec) {
+		if v, ok := g.thereIsConversion(sec, prim); ok {
+			// меняем местами направление методов
+			return &FieldMatchConversion{
+				MethodSecondary:      v.MethodPrimary,
+				SecondaryToPrimary:   v.PrimaryToSecondary,
+				SecondaryFromPrimary: v.PrimaryFromSecondary,
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// inPairPackage проверка, что именованный тип объявлен в пакете primary- или secondary-структуры
+func (g *Generator) inPairPackage(t types.Type) bool {
+	v, ok := t.(*types.Named)
+	if !ok || v.Obj().Pkg() == nil {
+		return false
+	}
+
+	path := v.Obj().Pkg().Path()
+	return path == g.prim.Obj().Pkg().Path() || path == g.sec.Obj().Pkg().Path()
+}
+
 // thereIsConversion поиск функций преобразования из типа prim или *prim в тип sec или *sec.
 // Это должен быть именованный тип. По предыдущим шагам в getTypeMatchDescription оба полученных на данном
 // этапе типов не являются указателями.
@@ -20,6 +55,11 @@ func (g *Generator) thereIsConversion(prim, sec types.Type) (*FieldMatchConversi
 			continue
 		}
 
+		// ранее сгенерированные конвертации будут перезаписаны и не могут использоваться
+		if g.isOverwritten(m.Pos()) {
+			continue
+		}
+
 		// у метода не должно быть параметров
 		sig := m.Type().(*types.Signature)
 		if sig.Params().Len() != 0 {
@@ -67,6 +107,10 @@ func (g *Generator) hasPrimToSecMethod(prim types.Type, sec types.Type, primscop
 			continue
 		}
 
+		if g.isOverwritten(f.Pos()) {
+			continue
+		}
+
 		sig := f.Type().(*types.Signature)
 		if sig.Recv() != nil {
 			// методы уже просматривали
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"go/types"
 	"strings"
 )
 
@@ -32,17 +33,17 @@ func (*FieldMatchDirect) isFieldMatchDescription() {}
 // FieldMatchConversion branch of FieldMatchDescription
 type FieldMatchConversion struct {
 	// MethodPrimary метод на primary-типе возвращающий значение secondary-типа
-	MethodPrimary string
+	MethodPrimary string `json:"method_primary,omitempty"`
 	// PrimaryToSecondary функция в пакете primary типа конвертирующая его в secondary
-	PrimaryToSecondary string
+	PrimaryToSecondary string `json:"primary_to_secondary,omitempty"`
 	// PrimaryFromSecondary функция в пакете primary типа возвращающая его значение из secondary
-	PrimaryFromSecondary string
+	PrimaryFromSecondary string `json:"primary_from_secondary,omitempty"`
 	// MethodPrimary метод на secondary-типе возвращающий значение primary-типа
-	MethodSecondary string
+	MethodSecondary string `json:"method_secondary,omitempty"`
 	// SecondaryToPrimary функция в пакете secondary-типа конвертирующая его в primary
-	SecondaryToPrimary string
+	SecondaryToPrimary string `json:"secondary_to_primary,omitempty"`
 	// SecondaryToPrimary функция в пакете secondary типа возвращающая его значение из primary
-	SecondaryFromPrimary string
+	SecondaryFromPrimary string `json:"secondary_from_primary,omitempty"`
 }
 
 func (c *FieldMatchConversion) String() string {
@@ -83,24 +84,69 @@ func (*FieldMatchConversion) isFieldMatchDescription() {}
 type FieldMatchEnum struct {
 	Primary   *enumDescription
 	Secondary *enumDescription
+	// ByName значения сопоставлены по именам констант
+	ByName bool
+	// Manual значения сопоставлены таблицами заданными вручную
+	Manual bool
+	// ProtoNames значения перечисления-строки являются названиями значений перечисления протобуфа
+	ProtoNames bool
+
+	// forward сопоставление значений primary → secondary, backward — secondary → primary
+	forward  []enumValueMatch
+	backward []enumValueMatch
 }
 
 func (e *FieldMatchEnum) String() string {
 	var values []string
-	for _, v := range e.Primary.values {
-		values = append(values, v.Val().ExactString())
+	for _, m := range e.forward {
+		values = append(values, m.from.Name()+" → "+m.to.Name())
+	}
+
+	if e.ProtoNames {
+		return fmt.Sprintf("enumeration matched by protobuf names %s", strings.Join(values, ", "))
 	}
 
-	return fmt.Sprintf("enumeration with values %s", strings.Join(values, ", "))
+	if e.Manual {
+		var back []string
+		for _, m := range e.backward {
+			back = append(back, m.from.Name()+" → "+m.to.Name())
+		}
+
+		return fmt.Sprintf(
+			"enumeration matched by table %s and back %s",
+			strings.Join(values, ", "),
+			strings.Join(back, ", "),
+		)
+	}
+
+	if e.ByName {
+		return fmt.Sprintf("enumeration matched by names %s", strings.Join(values, ", "))
+	}
+
+	return fmt.Sprintf("enumeration matched by values %s", strings.Join(values, ", "))
 }
 
 func (*FieldMatchEnum) isFieldMatchDescription() {}
 
 // FieldMatchCastable branch of FieldMatchDescription
-type FieldMatchCastable struct{}
+type FieldMatchCastable struct {
+	// Narrowing, NarrowingBack приведения primary → secondary и secondary → primary соответственно могут терять
+	// данные, см. isNarrowing
+	Narrowing     bool `json:"narrowing,omitempty"`
+	NarrowingBack bool `json:"narrowing_back,omitempty"`
+}
 
 func (a *FieldMatchCastable) String() string {
-	return "assignable types"
+	switch {
+	case a.Narrowing && a.NarrowingBack:
+		return "assignable types, narrowing in both directions"
+	case a.Narrowing:
+		return "assignable types, narrowing primary → secondary"
+	case a.NarrowingBack:
+		return "assignable types, narrowing secondary → primary"
+	default:
+		return "assignable types"
+	}
 }
 
 func (*FieldMatchCastable) isFieldMatchDescription() {}
@@ -128,6 +174,89 @@ func (m *FieldMatchMap) String() string {
 
 func (*FieldMatchMap) isFieldMatchDescription() {}
 
+// FieldMatchNested branch of FieldMatchDescription
+type FieldMatchNested struct {
+	// Primary, Secondary вложенные структуры, конвертация между которыми генерируется в том же запуске
+	Primary   string `json:"primary"`
+	Secondary string `json:"secondary"`
+
+	// conv генератор конвертаций вложенных структур
+	conv *Generator
+	// reflected описание для направления secondary → primary генератора conv
+	reflected bool
+}
+
+func (n *FieldMatchNested) String() string {
+	return fmt.Sprintf("nested structures %s and %s with generated conversions", n.Primary, n.Secondary)
+}
+
+func (*FieldMatchNested) isFieldMatchDescription() {}
+
+// FieldMatchWellKnown branch of FieldMatchDescription
+type FieldMatchWellKnown struct {
+	// Kind вид well-known типа протобуфа: timestamp, duration либо тип значения обёртки wrapperspb: double, float,
+	// int64, uint64, int32, uint32, bool, string, bytes
+	Kind string `json:"kind"`
+	// FromProto конвертация из well-known типа протобуфа в тип Go, иначе из типа Go в well-known тип
+	FromProto bool `json:"from_proto,omitempty"`
+
+	wk *wellKnownType
+}
+
+func (w *FieldMatchWellKnown) String() string {
+	if w.FromProto {
+		return fmt.Sprintf("protobuf well-known %s to Go type", w.Kind)
+	}
+
+	return fmt.Sprintf("Go type to protobuf well-known %s", w.Kind)
+}
+
+func (*FieldMatchWellKnown) isFieldMatchDescription() {}
+
+// FieldMatchUUID branch of FieldMatchDescription
+type FieldMatchUUID struct {
+	// Parse функция разбора UUID из строки
+	Parse string `json:"parse"`
+	// FromString конвертация из строки в UUID, иначе из UUID в строку
+	FromString bool `json:"from_string,omitempty"`
+
+	parse *types.Func
+}
+
+func (u *FieldMatchUUID) String() string {
+	if u.FromString {
+		return fmt.Sprintf("string to UUID with %s", u.Parse)
+	}
+
+	return "UUID to string"
+}
+
+func (*FieldMatchUUID) isFieldMatchDescription() {}
+
+// FieldMatchText branch of FieldMatchDescription
+type FieldMatchText struct {
+	// FromText конвертация из строки либо слайса байтов методом UnmarshalText, иначе в строку либо слайс байтов
+	// методом MarshalText
+	FromText bool `json:"from_text,omitempty"`
+	// Bytes текстовое представление является слайсом байтов, иначе строкой
+	Bytes bool `json:"bytes,omitempty"`
+}
+
+func (t *FieldMatchText) String() string {
+	repr := "string"
+	if t.Bytes {
+		repr = "bytes"
+	}
+
+	if t.FromText {
+		return fmt.Sprintf("%s to text unmarshaler", repr)
+	}
+
+	return fmt.Sprintf("text marshaler to %s", repr)
+}
+
+func (*FieldMatchText) isFieldMatchDescription() {}
+
 var (
 	_ FieldMatchDescription = &FieldMatchNoMatch{}
 	_ FieldMatchDescription = &FieldMatchDirect{}
@@ -136,4 +265,8 @@ var (
 	_ FieldMatchDescription = &FieldMatchCastable{}
 	_ FieldMatchDescription = &FieldMatchSlice{}
 	_ FieldMatchDescription = &FieldMatchMap{}
+	_ FieldMatchDescription = &FieldMatchNested{}
+	_ FieldMatchDescription = &FieldMatchWellKnown{}
+	_ FieldMatchDescription = &FieldMatchUUID{}
+	_ FieldMatchDescription = &FieldMatchText{}
 )
@@ -1,6 +1,15 @@
 package generator
 
-import "go/types"
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
 
 type enumMatchState int
 
@@ -16,7 +25,21 @@ const (
 	enumMatchStateMatched
 )
 
-func (g *Generator) matchEnums(prim, sec types.Type) (*enumDescription, *enumDescription, enumMatchState) {
+// enumValueMatch сопоставление константы одного перечисления константе другого
+type enumValueMatch struct {
+	from *types.Const
+	to   *types.Const
+}
+
+// matchEnums сопоставление перечислений. Значения сопоставляются в порядке уменьшения приоритета:
+//   • значения перечисления-строки с названиями значений перечисления протобуфа, см. matchProtoNames
+//   • по именам констант без префиксов с именами типов, например REGION_KIND_PRIMARY ↔ RegionKindPrimary
+//   • по совпадающим значениям констант, наборы значений должны совпадать полностью
+//   • для перечислений-строк по значениям одного из которых являются суффиксами значений другого, например
+//     DBRegionID ↔ RegionId
+// Для сопоставления по именам и суффиксам достаточно, чтобы все значения одного из перечислений нашли пару.
+// Сопоставления заданные вручную таблицами (см. WithEnumMapping) имеют приоритет над всеми остальными.
+func (g *Generator) matchEnums(prim, sec types.Type) (*FieldMatchEnum, enumMatchState) {
 	p := g.getEnumInfo(prim)
 	firstIsEnum := p != nil
 
@@ -26,30 +49,353 @@ func (g *Generator) matchEnums(prim, sec types.Type) (*enumDescription, *enumDes
 	if (firstIsEnum || secondIsEnum) && !(firstIsEnum && secondIsEnum) {
 		// случай, когда одно является енумием а другое нет автоматически означает
 		// что конвертации никакой не возможно
-		return nil, nil, enumMatchStateOneIsNotEnum
+		return nil, enumMatchStateOneIsNotEnum
 	}
 
 	if !firstIsEnum && !secondIsEnum {
 		// оба не являются енумиями и это означает что проверять можно дальше
-		return nil, nil, enumMatchStateNotEnums
+		return nil, enumMatchStateNotEnums
+	}
+
+	res := matchEnumsAuto(p, s)
+	if table := g.matchEnumTables(p, s, res); table != nil {
+		return table, enumMatchStateMatched
+	}
+
+	if res != nil {
+		return res, enumMatchStateMatched
+	}
+
+	return nil, enumMatchStateDifferentEnums
+}
+
+// matchEnumsAuto сопоставление перечислений без учёта таблиц заданных вручную
+func matchEnumsAuto(p, s *enumDescription) *FieldMatchEnum {
+	if res := matchProtoNames(p, s); res != nil {
+		return res
+	}
+
+	if matches := matchEnumValues(p, s, p.strippedName, s.strippedName, equalKeys); matches != nil {
+		return newFieldMatchEnum(p, s, matches, true)
+	}
+
+	if matches := matchEnumsByValues(p, s); matches != nil {
+		return newFieldMatchEnum(p, s, matches, false)
+	}
+
+	if p.isString() && s.isString() {
+		if matches := matchEnumValues(p, s, underscoredValue, underscoredValue, suffixKeys); matches != nil {
+			return newFieldMatchEnum(p, s, matches, false)
+		}
+	}
+
+	return nil
+}
+
+// matchEnumTables сопоставление перечислений по таблицам заданным вручную, nil если в таблицах нет записей для
+// данной пары перечислений. Значения без записи в таблице своего направления сопоставляются обращением записей
+// таблицы другого направления, если оно однозначно, и затем автоматическим сопоставлением auto, если оно есть.
+func (g *Generator) matchEnumTables(p, s *enumDescription, auto *FieldMatchEnum) *FieldMatchEnum {
+	forward := enumTableMatches(g.enumTo, g.enumToUsed, p, s)
+	backward := enumTableMatches(g.enumFrom, g.enumFromUsed, s, p)
+	if len(forward) == 0 && len(backward) == 0 {
+		return nil
+	}
+
+	var autoForward, autoBackward []enumValueMatch
+	if auto != nil {
+		autoForward = auto.forward
+		autoBackward = auto.backward
+	}
+
+	return &FieldMatchEnum{
+		Primary:   p,
+		Secondary: s,
+		Manual:    true,
+		forward:   mergeEnumMatches(forward, invertEnumMatches(backward), autoForward),
+		backward:  mergeEnumMatches(backward, invertEnumMatches(forward), autoBackward),
+	}
+}
+
+// enumTableMatches записи таблицы сопоставления констант относящиеся к конвертации перечисления from в to, ключи
+// таких записей отмечаются в used. Записи не относящиеся ни к одной паре перечислений отвергаются checkEnumTables.
+func enumTableMatches(table map[string]string, used map[string]struct{}, from, to *enumDescription) []enumValueMatch {
+	var res []enumValueMatch
+	for _, name := range from.names() {
+		toName, ok := table[name]
+		if !ok {
+			continue
+		}
+
+		c, ok := to.values[toName]
+		if !ok {
+			continue
+		}
+
+		used[name] = struct{}{}
+		res = append(res, enumValueMatch{
+			from: from.values[name],
+			to:   c,
+		})
+	}
+
+	return res
+}
+
+// checkEnumTables проверка, что каждая запись таблиц сопоставления констант перечислений сопоставила константы
+// какой-либо пары перечислений в структурах, в том числе вложенных. Так опечатки в названиях констант не приводят
+// молча к автоматическому сопоставлению.
+func (g *Generator) checkEnumTables() error {
+	if err := checkEnumTable(g.enumTo, g.enumToUsed); err != nil {
+		return errors.Wrap(err, "check primary → secondary mapping")
+	}
+
+	if err := checkEnumTable(g.enumFrom, g.enumFromUsed); err != nil {
+		return errors.Wrap(err, "check secondary → primary mapping")
+	}
+
+	return nil
+}
+
+func checkEnumTable(table map[string]string, used map[string]struct{}) error {
+	// записи проверяются в фиксированном порядке, чтобы при нескольких ошибках сообщение не менялось
+	names := make([]string, 0, len(table))
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := used[name]; !ok {
+			return errors.Newf("enum constants %s=%s do not belong to any pair of matched enums", name, table[name])
+		}
+	}
+
+	return nil
+}
+
+// invertEnumMatches обращение сопоставлений значений, значения в которые сопоставлено несколько других пропускаются
+func invertEnumMatches(matches []enumValueMatch) []enumValueMatch {
+	sources := map[string][]enumValueMatch{}
+	for _, m := range matches {
+		key := m.to.Val().ExactString()
+		sources[key] = append(sources[key], m)
+	}
+
+	var res []enumValueMatch
+	for _, m := range matches {
+		if len(sources[m.to.Val().ExactString()]) != 1 {
+			continue
+		}
+
+		res = append(res, enumValueMatch{
+			from: m.to,
+			to:   m.from,
+		})
+	}
+
+	return res
+}
+
+// mergeEnumMatches объединение сопоставлений значений в порядке уменьшения приоритета, на каждое значение
+// исходного перечисления остаётся одно сопоставление
+func mergeEnumMatches(lists ...[]enumValueMatch) []enumValueMatch {
+	var res []enumValueMatch
+	seen := map[string]struct{}{}
+	for _, list := range lists {
+		for _, m := range list {
+			if _, ok := seen[m.from.Val().ExactString()]; ok {
+				continue
+			}
+
+			seen[m.from.Val().ExactString()] = struct{}{}
+			res = append(res, m)
+		}
 	}
 
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].from.Name() < res[j].from.Name()
+	})
+
+	return res
+}
+
+// matchProtoNames сопоставление перечисления протобуфа и перечисления-строки, набор значений которого совпадает с
+// набором названий значений протобуфа. Такие перечисления конвертируются через словари <Enum>_name и <Enum>_value
+// пакета протобуфа.
+func matchProtoNames(p, s *enumDescription) *FieldMatchEnum {
+	var matches []enumValueMatch
+	switch {
+	case s.isProto && p.isString():
+		matches = matchEnumValues(p, s, stringValue, s.protoName, equalKeys)
+	case p.isProto && s.isString():
+		matches = matchEnumValues(p, s, p.protoName, stringValue, equalKeys)
+	default:
+		return nil
+	}
+
+	if len(matches) != len(p.values) || len(matches) != len(s.values) {
+		return nil
+	}
+
+	res := newFieldMatchEnum(p, s, matches, false)
+	res.ProtoNames = true
+	return res
+}
+
+// matchEnumsByValues сопоставление перечислений с одинаковыми наборами значений
+func matchEnumsByValues(p, s *enumDescription) []enumValueMatch {
 	if len(p.values) != len(s.values) {
-		return nil, nil, enumMatchStateDifferentEnums
+		return nil
 	}
 
-outer:
-	// каждое значение из p.values должно быть в s.values
-	for _, pv := range p.values {
-		for _, qv := range s.values {
-			if pv.Val().String() == qv.Val().String() {
-				continue outer
+	key := func(c *types.Const) string {
+		return c.Val().ExactString()
+	}
+	matches := matchEnumValues(p, s, key, key, equalKeys)
+	if len(matches) != len(p.values) {
+		return nil
+	}
+
+	return matches
+}
+
+// matchEnumValues сопоставление констант перечислений по ключам вычисленным из констант. Возвращает nil, если ни
+// для одного из перечислений не нашлось пар всем константам либо сопоставление неоднозначно.
+func matchEnumValues(
+	p, s *enumDescription,
+	pkey, skey func(c *types.Const) string,
+	eq func(a, b string) bool,
+) []enumValueMatch {
+	var res []enumValueMatch
+	smatched := map[string]struct{}{}
+	for _, pname := range p.names() {
+		pc := p.values[pname]
+
+		var candidates []*types.Const
+		for _, sname := range s.names() {
+			if sc := s.values[sname]; eq(pkey(pc), skey(sc)) {
+				candidates = append(candidates, sc)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+		default:
+			// константы с одинаковыми значениями однозначны, с разными — нет
+			for _, c := range candidates[1:] {
+				if !constant.Compare(c.Val(), token.EQL, candidates[0].Val()) {
+					return nil
+				}
 			}
 		}
 
-		// не нашли значения pv, выходим
-		return nil, nil, enumMatchStateDifferentEnums
+		res = append(res, enumValueMatch{
+			from: pc,
+			to:   candidates[0],
+		})
+		for _, c := range candidates {
+			smatched[c.Name()] = struct{}{}
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	if len(res) != len(p.values) && len(smatched) != len(s.values) {
+		return nil
+	}
+
+	return res
+}
+
+// newFieldMatchEnum описание сопоставленных перечислений. Для каждого направления конвертации остаётся по одному
+// сопоставлению на каждое значение исходного перечисления, т.к. значения в switch не могут повторяться.
+func newFieldMatchEnum(p, s *enumDescription, matches []enumValueMatch, byName bool) *FieldMatchEnum {
+	res := &FieldMatchEnum{
+		Primary:   p,
+		Secondary: s,
+		ByName:    byName,
+	}
+
+	seen := map[string]struct{}{}
+	for _, m := range matches {
+		if _, ok := seen[m.from.Val().ExactString()]; ok {
+			continue
+		}
+
+		seen[m.from.Val().ExactString()] = struct{}{}
+		res.forward = append(res.forward, m)
+	}
+
+	seen = map[string]struct{}{}
+	for _, m := range matches {
+		if _, ok := seen[m.to.Val().ExactString()]; ok {
+			continue
+		}
+
+		seen[m.to.Val().ExactString()] = struct{}{}
+		res.backward = append(res.backward, enumValueMatch{
+			from: m.to,
+			to:   m.from,
+		})
+	}
+
+	sort.Slice(res.backward, func(i, j int) bool {
+		return res.backward[i].from.Name() < res.backward[j].from.Name()
+	})
+
+	return res
+}
+
+// strippedName имя константы перечисления без префиксов с именем типа в виде matiss.Underscored. Для перечислений
+// протобуфа префикс встречается дважды: RegionKind_REGION_KIND_PRIMARY.
+func (d *enumDescription) strippedName(c *types.Const) string {
+	prefix := matiss.Underscored(d.orig.(*types.Named).Obj().Name()) + "_"
+	name := matiss.Underscored(c.Name())
+	for strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+		name = strings.TrimPrefix(name, prefix)
 	}
 
-	return p, s, enumMatchStateMatched
+	return name
+}
+
+// protoName название значения перечисления протобуфа, под которым оно находится в словарях <Enum>_name и
+// <Enum>_value. Константы перечислений верхнего уровня имеют префикс <Enum>_, вложенных в сообщения — префикс с
+// названием сообщения: Message_Kind и Message_KIND_PRIMARY.
+func (d *enumDescription) protoName(c *types.Const) string {
+	name := d.orig.(*types.Named).Obj().Name()
+	prefix := name + "_"
+	if i := strings.LastIndex(name, "_"); i >= 0 {
+		prefix = name[:i+1]
+	}
+
+	return strings.TrimPrefix(c.Name(), prefix)
+}
+
+// isString проверка, что значения перечисления являются строками
+func (d *enumDescription) isString() bool {
+	return isString(d.orig)
+}
+
+// stringValue значение константы-строки
+func stringValue(c *types.Const) string {
+	return constant.StringVal(c.Val())
+}
+
+// underscoredValue значение константы-строки в виде matiss.Underscored
+func underscoredValue(c *types.Const) string {
+	return matiss.Underscored(constant.StringVal(c.Val()))
+}
+
+func equalKeys(a, b string) bool {
+	return a == b
+}
+
+// suffixKeys проверка, что один из ключей в виде matiss.Underscored является суффиксом другого
+func suffixKeys(a, b string) bool {
+	return a == b || strings.HasSuffix(a, "_"+b) || strings.HasSuffix(b, "_"+a)
 }
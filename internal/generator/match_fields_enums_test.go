@@ -0,0 +1,424 @@
+package generator
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_enumDescription_strippedName(t *testing.T) {
+	pkg := types.NewPackage("example.com/pb", "pb")
+	typ := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "RegionKind", nil), types.Typ[types.Int32], nil)
+	d := &enumDescription{orig: typ}
+
+	tests := []struct {
+		name  string
+		konst string
+		want  string
+	}{
+		{
+			name:  "go",
+			konst: "RegionKindPrimary",
+			want:  "primary",
+		},
+		{
+			name:  "proto",
+			konst: "RegionKind_REGION_KIND_PRIMARY",
+			want:  "primary",
+		},
+		{
+			name:  "no-prefix",
+			konst: "Primary",
+			want:  "primary",
+		},
+		{
+			name:  "prefix-only",
+			konst: "RegionKind_REGION_KIND",
+			want:  "region_kind",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := types.NewConst(token.NoPos, pkg, tt.konst, typ, constant.MakeInt64(1))
+			if got := d.strippedName(c); got != tt.want {
+				t.Errorf("strippedName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_suffixKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{
+			name: "equal",
+			a:    "region_id",
+			b:    "region_id",
+			want: true,
+		},
+		{
+			name: "suffix",
+			a:    "db_region_id",
+			b:    "region_id",
+			want: true,
+		},
+		{
+			name: "reversed-suffix",
+			a:    "id",
+			b:    "region_id",
+			want: true,
+		},
+		{
+			name: "partial-word",
+			a:    "dbregion_id",
+			b:    "region_id",
+			want: false,
+		},
+		{
+			name: "different",
+			a:    "region_id",
+			b:    "user_name",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suffixKeys(tt.a, tt.b); got != tt.want {
+				t.Errorf("suffixKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchProtoNames(t *testing.T) {
+	pb := types.NewPackage("example.com/pb", "pb")
+	proto := newTestEnum(pb, "RegionKind", types.Typ[types.Int32], map[string]constant.Value{
+		"RegionKind_REGION_KIND_UNSPECIFIED": constant.MakeInt64(0),
+		"RegionKind_REGION_KIND_PRIMARY":     constant.MakeInt64(1),
+	})
+	newTestProtoMaps(pb, "RegionKind")
+	nested := newTestEnum(pb, "Region_Kind", types.Typ[types.Int32], map[string]constant.Value{
+		"Region_KIND_UNSPECIFIED": constant.MakeInt64(0),
+		"Region_KIND_PRIMARY":     constant.MakeInt64(1),
+	})
+	newTestProtoMaps(pb, "Region_Kind")
+
+	domain := types.NewPackage("example.com/domain", "domain")
+	names := newTestEnum(domain, "Kind", types.Typ[types.String], map[string]constant.Value{
+		"KindUnspecified": constant.MakeString("REGION_KIND_UNSPECIFIED"),
+		"KindPrimary":     constant.MakeString("REGION_KIND_PRIMARY"),
+	})
+	nestedNames := newTestEnum(domain, "NestedKind", types.Typ[types.String], map[string]constant.Value{
+		"NestedKindUnspecified": constant.MakeString("KIND_UNSPECIFIED"),
+		"NestedKindPrimary":     constant.MakeString("KIND_PRIMARY"),
+	})
+	partial := newTestEnum(domain, "PartialKind", types.Typ[types.String], map[string]constant.Value{
+		"PartialKindPrimary": constant.MakeString("REGION_KIND_PRIMARY"),
+	})
+
+	tests := []struct {
+		name string
+		prim types.Type
+		sec  types.Type
+		want map[string]string
+	}{
+		{
+			name: "to-proto",
+			prim: names,
+			sec:  proto,
+			want: map[string]string{
+				"KindUnspecified": "RegionKind_REGION_KIND_UNSPECIFIED",
+				"KindPrimary":     "RegionKind_REGION_KIND_PRIMARY",
+			},
+		},
+		{
+			name: "from-proto",
+			prim: proto,
+			sec:  names,
+			want: map[string]string{
+				"RegionKind_REGION_KIND_UNSPECIFIED": "KindUnspecified",
+				"RegionKind_REGION_KIND_PRIMARY":     "KindPrimary",
+			},
+		},
+		{
+			name: "nested-proto",
+			prim: nestedNames,
+			sec:  nested,
+			want: map[string]string{
+				"NestedKindUnspecified": "Region_KIND_UNSPECIFIED",
+				"NestedKindPrimary":     "Region_KIND_PRIMARY",
+			},
+		},
+		{
+			name: "partial",
+			prim: partial,
+			sec:  proto,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{}
+			res := matchProtoNames(g.getEnumInfo(tt.prim), g.getEnumInfo(tt.sec))
+			if tt.want == nil {
+				if res != nil {
+					t.Errorf("no match expected, got %s", res)
+				}
+				return
+			}
+
+			if res == nil {
+				t.Fatal("match expected")
+			}
+
+			got := map[string]string{}
+			for _, m := range res.forward {
+				got[m.from.Name()] = m.to.Name()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchProtoNames() = %v, want %v", got, tt.want)
+			}
+			if len(res.backward) != len(tt.want) {
+				t.Errorf("%d backward matches expected, got %d", len(tt.want), len(res.backward))
+			}
+		})
+	}
+}
+
+func newTestEnum(pkg *types.Package, name string, underlying types.Type, values map[string]constant.Value) *types.Named {
+	typ := types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), underlying, nil)
+	pkg.Scope().Insert(typ.Obj())
+	for cname, value := range values {
+		pkg.Scope().Insert(types.NewConst(token.NoPos, pkg, cname, typ, value))
+	}
+
+	return typ
+}
+
+func newTestProtoMaps(pkg *types.Package, name string) {
+	str := types.Typ[types.String]
+	i32 := types.Typ[types.Int32]
+	pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, name+"_name", types.NewMap(i32, str)))
+	pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, name+"_value", types.NewMap(str, i32)))
+}
+
+func Test_enumTableMatches(t *testing.T) {
+	domain := types.NewPackage("example.com/domain", "domain")
+	kind := newTestEnum(domain, "Kind", types.Typ[types.Int], map[string]constant.Value{
+		"KindPrimary": constant.MakeInt64(1),
+		"KindBackup":  constant.MakeInt64(2),
+		"KindLegacy":  constant.MakeInt64(3),
+	})
+	pb := types.NewPackage("example.com/pb", "pb")
+	proto := newTestEnum(pb, "Kind", types.Typ[types.Int32], map[string]constant.Value{
+		"Kind_KIND_MAIN":   constant.MakeInt64(1),
+		"Kind_KIND_RESERV": constant.MakeInt64(2),
+	})
+
+	tests := []struct {
+		name     string
+		table    map[string]string
+		want     map[string]string
+		wantUsed []string
+	}{
+		{
+			name: "several-to-one",
+			table: map[string]string{
+				"KindPrimary": "Kind_KIND_MAIN",
+				"KindBackup":  "Kind_KIND_MAIN",
+			},
+			want: map[string]string{
+				"KindPrimary": "Kind_KIND_MAIN",
+				"KindBackup":  "Kind_KIND_MAIN",
+			},
+			wantUsed: []string{"KindBackup", "KindPrimary"},
+		},
+		{
+			// записи с неизвестными константами не отмечаются использованными и отвергаются checkEnumTables
+			name: "unknown-constants",
+			table: map[string]string{
+				"KindPrimary": "Kind_KIND_RESERV",
+				"KindLegacy":  "Kind_KIND_LEGACY",
+				"KindOther":   "Kind_KIND_MAIN",
+			},
+			want: map[string]string{
+				"KindPrimary": "Kind_KIND_RESERV",
+			},
+			wantUsed: []string{"KindPrimary"},
+		},
+		{
+			name:     "empty",
+			table:    nil,
+			want:     map[string]string{},
+			wantUsed: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{}
+			used := map[string]struct{}{}
+			got := map[string]string{}
+			for _, m := range enumTableMatches(tt.table, used, g.getEnumInfo(kind), g.getEnumInfo(proto)) {
+				got[m.from.Name()] = m.to.Name()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("enumTableMatches() = %v, want %v", got, tt.want)
+			}
+
+			gotUsed := []string{}
+			for name := range used {
+				gotUsed = append(gotUsed, name)
+			}
+			sort.Strings(gotUsed)
+			if !reflect.DeepEqual(gotUsed, tt.wantUsed) {
+				t.Errorf("enumTableMatches() used = %v, want %v", gotUsed, tt.wantUsed)
+			}
+
+			err := checkEnumTable(tt.table, used)
+			if wantErr := len(tt.wantUsed) != len(tt.table); (err != nil) != wantErr {
+				t.Errorf("checkEnumTable() error = %v, wantErr %v", err, wantErr)
+			}
+		})
+	}
+}
+
+func Test_mergeEnumMatches(t *testing.T) {
+	domain := types.NewPackage("example.com/domain", "domain")
+	kind := newTestEnum(domain, "Kind", types.Typ[types.Int], map[string]constant.Value{
+		"KindPrimary": constant.MakeInt64(1),
+		"KindBackup":  constant.MakeInt64(2),
+	})
+	pb := types.NewPackage("example.com/pb", "pb")
+	proto := newTestEnum(pb, "Kind", types.Typ[types.Int32], map[string]constant.Value{
+		"Kind_KIND_MAIN":   constant.MakeInt64(1),
+		"Kind_KIND_RESERV": constant.MakeInt64(2),
+	})
+
+	g := &Generator{}
+	k, p := g.getEnumInfo(kind), g.getEnumInfo(proto)
+	match := func(from, to string) enumValueMatch {
+		return enumValueMatch{from: k.values[from], to: p.values[to]}
+	}
+
+	tests := []struct {
+		name  string
+		lists [][]enumValueMatch
+		want  []string
+	}{
+		{
+			name: "priority",
+			lists: [][]enumValueMatch{
+				{match("KindPrimary", "Kind_KIND_RESERV")},
+				{match("KindPrimary", "Kind_KIND_MAIN"), match("KindBackup", "Kind_KIND_RESERV")},
+			},
+			want: []string{"KindBackup=Kind_KIND_RESERV", "KindPrimary=Kind_KIND_RESERV"},
+		},
+		{
+			name: "first-of-list",
+			lists: [][]enumValueMatch{
+				{match("KindBackup", "Kind_KIND_MAIN"), match("KindBackup", "Kind_KIND_RESERV")},
+			},
+			want: []string{"KindBackup=Kind_KIND_MAIN"},
+		},
+		{
+			name:  "empty",
+			lists: [][]enumValueMatch{nil, nil},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range mergeEnumMatches(tt.lists...) {
+				got = append(got, m.from.Name()+"="+m.to.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeEnumMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_protoEnumGenerated(t *testing.T) {
+	files := runGenerated(
+		t,
+		"protoenum",
+		testdataPair("protoenum", "Account", "AccountPB", WithOutput("error_convgen.go")),
+		testdataPair(
+			"protoenum",
+			"Account",
+			"AccountDefault",
+			WithOutput("default_convgen.go"),
+			WithEnumFallbackFor("StatusPB", EnumFallback{kind: enumFallbackDefault}),
+		),
+	)
+
+	// значения конвертируются через словари протобуфа, а не сопоставлением констант
+	content := files["internal/generator/testdata/protoenum/error_convgen.go"]
+	for _, want := range []string{"StatusPB_value[", "StatusPB_name["} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code must use %s:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerator_enumTablesUnknownConstants(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      map[string]string
+		from    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown-source",
+			to:      map[string]string{"KindPrimary": "KindPBMain", "KindPrimry": "KindPBReserve"},
+			wantErr: "KindPrimry=KindPBReserve",
+		},
+		{
+			name:    "unknown-target",
+			from:    map[string]string{"KindPBMain": "KindMain"},
+			wantErr: "KindPBMain=KindMain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := generateTestdata(
+				t,
+				testdataPair("enumtables", "Storage", "StoragePB", WithEnumMapping(tt.to, tt.from)),
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("generation error = %v, must mention %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerator_enumTablesGenerated(t *testing.T) {
+	runGenerated(
+		t,
+		"enumtables",
+		testdataPair(
+			"enumtables",
+			"Storage",
+			"StoragePB",
+			WithEnumMapping(
+				map[string]string{
+					"KindPrimary": "KindPBMain",
+					"KindBackup":  "KindPBReserve",
+					"KindLegacy":  "KindPBMain",
+				},
+				map[string]string{
+					"KindPBMain": "KindPrimary",
+				},
+			),
+		),
+	)
+}
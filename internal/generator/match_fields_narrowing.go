@@ -0,0 +1,90 @@
+package generator
+
+import "go/types"
+
+// newFieldMatchCastable описание сопоставления числовых типов приводимых друг к другу с пометкой сужающих
+// направлений конвертации
+func newFieldMatchCastable(prim, sec types.Type) *FieldMatchCastable {
+	return &FieldMatchCastable{
+		Narrowing:     isNarrowing(prim, sec),
+		NarrowingBack: isNarrowing(sec, prim),
+	}
+}
+
+// isNarrowing проверка, что приведение значения числового типа src к типу dst может терять данные:
+//   • целые типы меньшей размерности либо другой знаковости, int и uint считаются 64-битными
+//   • целые типы из типов с плавающей точкой
+//   • типы с плавающей точкой из целых типов, значения которых не представимы точно
+//   • float32 из float64
+func isNarrowing(src, dst types.Type) bool {
+	s, ok := src.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+
+	d, ok := dst.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+
+	sinfo, ok := numericInfo(s)
+	if !ok {
+		return false
+	}
+
+	dinfo, ok := numericInfo(d)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case sinfo.float && dinfo.float:
+		return dinfo.bits < sinfo.bits
+	case sinfo.float:
+		return true
+	case dinfo.float:
+		return sinfo.bits > dinfo.mantissa
+	case sinfo.signed && !dinfo.signed:
+		return true
+	case !sinfo.signed && dinfo.signed:
+		return dinfo.bits <= sinfo.bits
+	default:
+		return dinfo.bits < sinfo.bits
+	}
+}
+
+// numeric описание числового типа
+type numeric struct {
+	bits   int
+	signed bool
+	float  bool
+	// mantissa количество бит целого числа представимых типом с плавающей точкой точно
+	mantissa int
+}
+
+func numericInfo(t *types.Basic) (numeric, bool) {
+	switch t.Kind() {
+	case types.Int8:
+		return numeric{bits: 8, signed: true}, true
+	case types.Int16:
+		return numeric{bits: 16, signed: true}, true
+	case types.Int32:
+		return numeric{bits: 32, signed: true}, true
+	case types.Int, types.Int64:
+		return numeric{bits: 64, signed: true}, true
+	case types.Uint8:
+		return numeric{bits: 8}, true
+	case types.Uint16:
+		return numeric{bits: 16}, true
+	case types.Uint32:
+		return numeric{bits: 32}, true
+	case types.Uint, types.Uint64, types.Uintptr:
+		return numeric{bits: 64}, true
+	case types.Float32:
+		return numeric{bits: 32, signed: true, float: true, mantissa: 24}, true
+	case types.Float64:
+		return numeric{bits: 64, signed: true, float: true, mantissa: 53}, true
+	default:
+		return numeric{}, false
+	}
+}
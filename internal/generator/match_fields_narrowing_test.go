@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"go/types"
+	"testing"
+)
+
+func Test_isNarrowing(t *testing.T) {
+	tests := []struct {
+		name string
+		src  types.BasicKind
+		dst  types.BasicKind
+		want bool
+	}{
+		{
+			name: "int32-int64",
+			src:  types.Int32,
+			dst:  types.Int64,
+			want: false,
+		},
+		{
+			name: "int64-int32",
+			src:  types.Int64,
+			dst:  types.Int32,
+			want: true,
+		},
+		{
+			name: "int-int64",
+			src:  types.Int,
+			dst:  types.Int64,
+			want: false,
+		},
+		{
+			name: "int8-uint64",
+			src:  types.Int8,
+			dst:  types.Uint64,
+			want: true,
+		},
+		{
+			name: "uint32-int64",
+			src:  types.Uint32,
+			dst:  types.Int64,
+			want: false,
+		},
+		{
+			name: "uint32-int32",
+			src:  types.Uint32,
+			dst:  types.Int32,
+			want: true,
+		},
+		{
+			name: "float64-uint8",
+			src:  types.Float64,
+			dst:  types.Uint8,
+			want: true,
+		},
+		{
+			name: "int32-float64",
+			src:  types.Int32,
+			dst:  types.Float64,
+			want: false,
+		},
+		{
+			name: "int32-float32",
+			src:  types.Int32,
+			dst:  types.Float32,
+			want: true,
+		},
+		{
+			name: "int16-float32",
+			src:  types.Int16,
+			dst:  types.Float32,
+			want: false,
+		},
+		{
+			name: "int64-float64",
+			src:  types.Int64,
+			dst:  types.Float64,
+			want: true,
+		},
+		{
+			name: "float64-float32",
+			src:  types.Float64,
+			dst:  types.Float32,
+			want: true,
+		},
+		{
+			name: "float32-float64",
+			src:  types.Float32,
+			dst:  types.Float64,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNarrowing(types.Typ[tt.src], types.Typ[tt.dst]); got != tt.want {
+				t.Errorf("isNarrowing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_narrowingGenerated(t *testing.T) {
+	runGenerated(t, "narrowing", testdataPair("narrowing", "Sample", "SamplePB", WithNarrowingChecks()))
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestGenerator_uncoveredSecondaryFields(t *testing.T) {
+	str := types.Typ[types.String]
+	i64 := types.Typ[types.Int64]
+	domain := types.NewPackage("example.com/domain", "domain")
+	pb := types.NewPackage("example.com/pb", "pb")
+	field := func(pkg *types.Package, name string, typ types.Type) *types.Var {
+		return types.NewField(token.NoPos, pkg, name, typ, false)
+	}
+
+	primID, primName, primExtra, primLegacy := field(domain, "ID", str), field(domain, "Name", str),
+		field(domain, "Extra", str), field(domain, "Legacy", str)
+	secID, secName, secOther, secLegacy := field(pb, "ID", str), field(pb, "Name", i64),
+		field(pb, "Other", str), field(pb, "Legacy", i64)
+	sec := types.NewStruct([]*types.Var{secID, secName, secOther, secLegacy}, nil)
+	g := &Generator{
+		sec: types.NewNamed(types.NewTypeName(token.NoPos, pb, "User", nil), sec, nil),
+	}
+
+	tests := []struct {
+		name string
+		ms   []fieldMatchInfo
+		want []string
+	}{
+		{
+			name: "all-matched",
+			ms: []fieldMatchInfo{
+				{prim: primID, sec: secID, descr: &FieldMatchDirect{}},
+				{prim: primName, sec: secName, descr: &FieldMatchDirect{}},
+				{prim: primExtra, sec: secOther, descr: &FieldMatchDirect{}},
+				{prim: primLegacy, sec: secLegacy, descr: &FieldMatchDirect{}},
+			},
+			want: nil,
+		},
+		{
+			name: "non-equivalent-type",
+			ms: []fieldMatchInfo{
+				{prim: primID, sec: secID, descr: &FieldMatchDirect{}},
+				{prim: primName, sec: secName, descr: &FieldMatchNoMatch{}},
+				{prim: primExtra, sec: secOther, descr: &FieldMatchDirect{}},
+				{prim: primLegacy, sec: secLegacy, descr: &FieldMatchDirect{}},
+			},
+			want: []string{"Name"},
+		},
+		{
+			name: "unmatched-primary-does-not-cover",
+			ms: []fieldMatchInfo{
+				{prim: primID, sec: secID, descr: &FieldMatchDirect{}},
+				{prim: primName, sec: secName, descr: &FieldMatchDirect{}},
+				{prim: primExtra, descr: &FieldMatchNoMatch{}},
+				{prim: primLegacy, sec: secLegacy, descr: &FieldMatchDirect{}},
+			},
+			want: []string{"Other"},
+		},
+		{
+			name: "excluded-from-secondary",
+			ms: []fieldMatchInfo{
+				{prim: primID, sec: secID, descr: &FieldMatchDirect{}},
+				{prim: primName, sec: secName, descr: &FieldMatchDirect{}},
+				{prim: primExtra, sec: secOther, descr: &FieldMatchDirect{}},
+				{prim: primLegacy, sec: secLegacy, descr: &FieldMatchNoMatch{}, excludeFrom: true},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range g.uncoveredSecondaryFields(tt.ms, nil) {
+				got = append(got, f.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uncoveredSecondaryFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_checkManualMatches(t *testing.T) {
+	str := types.Typ[types.String]
+	domain := types.NewPackage("example.com/domain", "domain")
+	pb := types.NewPackage("example.com/pb", "pb")
+	newStruct := func(pkg *types.Package, name string, fields ...string) *types.Named {
+		var vars []*types.Var
+		for _, f := range fields {
+			vars = append(vars, types.NewField(token.NoPos, pkg, f, str, false))
+		}
+		return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(vars, nil), nil)
+	}
+	prim := newStruct(domain, "User", "RegionID", "Name")
+	sec := newStruct(pb, "User", "RegionId", "FullName", "Nick")
+
+	tests := []struct {
+		name    string
+		matches map[string]string
+		ignored []string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			matches: map[string]string{"Name": "FullName"},
+			ignored: []string{"Nick"},
+		},
+		{
+			name:    "unknown-primary-field",
+			matches: map[string]string{"Title": "FullName"},
+			wantErr: "primary structure example.com/domain.User has no field Title",
+		},
+		{
+			name:    "unknown-secondary-field",
+			matches: map[string]string{"Name": "DisplayName"},
+			wantErr: "secondary structure example.com/pb.User has no field DisplayName",
+		},
+		{
+			name:    "duplicate-primary-field",
+			matches: map[string]string{"RegionID": "FullName", "RegionId": "Nick"},
+			wantErr: "manual matches RegionID=FullName and RegionId=Nick refer to the same primary field",
+		},
+		{
+			name:    "duplicate-secondary-field",
+			matches: map[string]string{"Name": "FullName", "RegionID": "FullName"},
+			wantErr: "manual matches Name=FullName and RegionID=FullName refer to the same secondary field",
+		},
+		{
+			name:    "unknown-ignored-field",
+			ignored: []string{"DisplayName"},
+			wantErr: "ignored field DisplayName is missing in both example.com/domain.User and example.com/pb.User",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{
+				prim: prim,
+				sec:  sec,
+			}
+			WithManualMatches(tt.matches)(g)
+			WithIgnoredFields(tt.ignored...)(g)
+
+			err := g.checkManualMatches()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkManualMatches() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkManualMatches() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerator_explicitConversionsGenerated(t *testing.T) {
+	runGenerated(t, "explicit", testdataPair("explicit", "Invoice", "InvoicePB"))
+}
+
+func TestGenerator_mapKeysGenerated(t *testing.T) {
+	runGenerated(
+		t,
+		"mapkeys",
+		testdataPair(
+			"mapkeys",
+			"Zone",
+			"ZonePB",
+			WithEnumMapping(
+				map[string]string{
+					"KindPrimary": "KindPBMain",
+					"KindBackup":  "KindPBMain",
+				},
+				nil,
+			),
+		),
+	)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import "go/types"
+
+// isTextType проверка, что тип реализует encoding.TextMarshaler, а указатель на него — encoding.TextUnmarshaler.
+// Типы на основе строк и слайсов байтов не рассматриваются, они приводятся к строкам и слайсам байтов напрямую.
+func isTextType(t types.Type) bool {
+	v, ok := t.(*types.Named)
+	if !ok || v.Obj().Pkg() == nil || isString(v) || isBytes(v) {
+		return false
+	}
+
+	bytesType := types.NewSlice(types.Typ[types.Byte])
+	errType := types.Universe.Lookup("error").Type()
+	methods := types.NewMethodSet(types.NewPointer(v))
+
+	marshal := methods.Lookup(v.Obj().Pkg(), "MarshalText")
+	if marshal == nil {
+		return false
+	}
+	sig := marshal.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 2 {
+		return false
+	}
+	if !types.Identical(sig.Results().At(0).Type(), bytesType) || !types.Identical(sig.Results().At(1).Type(), errType) {
+		return false
+	}
+
+	unmarshal := methods.Lookup(v.Obj().Pkg(), "UnmarshalText")
+	if unmarshal == nil {
+		return false
+	}
+	sig = unmarshal.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	if !types.Identical(sig.Params().At(0).Type(), bytesType) || !types.Identical(sig.Results().At(0).Type(), errType) {
+		return false
+	}
+
+	return true
+}
+
+// matchText сопоставление типов реализующих encoding.TextMarshaler и encoding.TextUnmarshaler строкам и слайсам
+// байтов. Указатели на данном этапе уже сняты.
+func matchText(prim, sec types.Type) (*FieldMatchText, bool) {
+	switch {
+	case isTextType(prim) && (isString(sec) || isBytes(sec)):
+		return &FieldMatchText{
+			Bytes: isBytes(sec),
+		}, true
+	case isTextType(sec) && (isString(prim) || isBytes(prim)):
+		return &FieldMatchText{
+			FromText: true,
+			Bytes:    isBytes(prim),
+		}, true
+	}
+
+	return nil, false
+}
+
+// isBytes проверка, что underlying-тип является слайсом байтов
+func isBytes(t types.Type) bool {
+	return types.Identical(t.Underlying(), types.NewSlice(types.Typ[types.Byte]))
+}
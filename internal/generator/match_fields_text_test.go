@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"go/types"
+	"testing"
+)
+
+func Test_isTextType(t *testing.T) {
+	pkg := newTestSourcePackage(t, "example.com/text", `package text
+
+type Addr struct{}
+
+func (a Addr) MarshalText() ([]byte, error) { return nil, nil }
+
+func (a *Addr) UnmarshalText(b []byte) error { return nil }
+
+type MarshalOnly struct{}
+
+func (m MarshalOnly) MarshalText() ([]byte, error) { return nil, nil }
+
+type BadUnmarshal struct{}
+
+func (b BadUnmarshal) MarshalText() ([]byte, error) { return nil, nil }
+
+func (b *BadUnmarshal) UnmarshalText(s string) error { return nil }
+
+type Name string
+
+func (n Name) MarshalText() ([]byte, error) { return nil, nil }
+
+func (n *Name) UnmarshalText(b []byte) error { return nil }
+`)
+
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{
+			name: "marshaler",
+			typ:  pkg.Scope().Lookup("Addr").Type(),
+			want: true,
+		},
+		{
+			name: "marshal-only",
+			typ:  pkg.Scope().Lookup("MarshalOnly").Type(),
+		},
+		{
+			name: "bad-unmarshal",
+			typ:  pkg.Scope().Lookup("BadUnmarshal").Type(),
+		},
+		{
+			name: "string-based",
+			typ:  pkg.Scope().Lookup("Name").Type(),
+		},
+		{
+			name: "pointer",
+			typ:  types.NewPointer(pkg.Scope().Lookup("Addr").Type()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTextType(tt.typ); got != tt.want {
+				t.Errorf("isTextType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_textGenerated(t *testing.T) {
+	runGenerated(t, "text", testdataPair("text", "Palette", "PalettePB"))
+}
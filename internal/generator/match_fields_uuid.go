@@ -0,0 +1,69 @@
+package generator
+
+import "go/types"
+
+// uuidType проверка, что тип является UUID: именованный тип на основе [16]byte с методом String() string и
+// функцией Parse(string) (T, error) в его пакете. Возвращает функцию разбора UUID из строки.
+func uuidType(t types.Type) *types.Func {
+	v, ok := t.(*types.Named)
+	if !ok || v.Obj().Pkg() == nil {
+		return nil
+	}
+
+	arr, ok := v.Underlying().(*types.Array)
+	if !ok || arr.Len() != 16 || !types.Identical(arr.Elem(), types.Typ[types.Byte]) {
+		return nil
+	}
+
+	str := types.NewMethodSet(v).Lookup(v.Obj().Pkg(), "String")
+	if str == nil {
+		return nil
+	}
+	sig := str.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 || !isString(sig.Results().At(0).Type()) {
+		return nil
+	}
+
+	parse, ok := v.Obj().Pkg().Scope().Lookup("Parse").(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig = parse.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || !isString(sig.Params().At(0).Type()) {
+		return nil
+	}
+	if sig.Results().Len() != 2 || !types.Identical(sig.Results().At(0).Type(), v) {
+		return nil
+	}
+	if !types.Identical(sig.Results().At(1).Type(), types.Universe.Lookup("error").Type()) {
+		return nil
+	}
+
+	return parse
+}
+
+// matchUUID сопоставление UUID и строк. Указатели на данном этапе уже сняты.
+func matchUUID(prim, sec types.Type) (*FieldMatchUUID, bool) {
+	if parse := uuidType(prim); parse != nil && isString(sec) {
+		return &FieldMatchUUID{
+			Parse: parse.FullName(),
+			parse: parse,
+		}, true
+	}
+
+	if parse := uuidType(sec); parse != nil && isString(prim) {
+		return &FieldMatchUUID{
+			Parse:      parse.FullName(),
+			FromString: true,
+			parse:      parse,
+		}, true
+	}
+
+	return nil, false
+}
+
+// isString проверка, что underlying-тип является строкой
+func isString(t types.Type) bool {
+	v, ok := t.Underlying().(*types.Basic)
+	return ok && v.Kind() == types.String
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// newTestSourcePackage пакет с данным исходным кодом
+func newTestSourcePackage(t *testing.T, path string, src string) *types.Package {
+	t.Helper()
+
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse source of %s: %v", path, err)
+	}
+
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check(path, fs, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("check source of %s: %v", path, err)
+	}
+
+	return pkg
+}
+
+func Test_uuidType(t *testing.T) {
+	uuid := newTestSourcePackage(t, "example.com/uuid", `package uuid
+
+type UUID [16]byte
+
+func (u UUID) String() string { return "" }
+
+func Parse(s string) (UUID, error) { return UUID{}, nil }
+
+type Short [8]byte
+
+func (s Short) String() string { return "" }
+
+type NoString [16]byte
+`)
+	noparse := newTestSourcePackage(t, "example.com/noparse", `package noparse
+
+type UUID [16]byte
+
+func (u UUID) String() string { return "" }
+`)
+	badparse := newTestSourcePackage(t, "example.com/badparse", `package badparse
+
+type UUID [16]byte
+
+func (u UUID) String() string { return "" }
+
+func Parse(s string) UUID { return UUID{} }
+`)
+
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{
+			name: "uuid",
+			typ:  uuid.Scope().Lookup("UUID").Type(),
+			want: true,
+		},
+		{
+			name: "short-array",
+			typ:  uuid.Scope().Lookup("Short").Type(),
+		},
+		{
+			name: "no-string-method",
+			typ:  uuid.Scope().Lookup("NoString").Type(),
+		},
+		{
+			name: "no-parse",
+			typ:  noparse.Scope().Lookup("UUID").Type(),
+		},
+		{
+			name: "parse-without-error",
+			typ:  badparse.Scope().Lookup("UUID").Type(),
+		},
+		{
+			name: "unnamed-array",
+			typ:  types.NewArray(types.Typ[types.Byte], 16),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uuidType(tt.typ); (got != nil) != tt.want {
+				t.Errorf("uuidType() = %v, want parse function %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_uuidGenerated(t *testing.T) {
+	runGenerated(t, "uuid", testdataPair("uuid", "Account", "AccountPB"))
+}
@@ -0,0 +1,131 @@
+package generator
+
+import "go/types"
+
+const (
+	timestamppbPath = "google.golang.org/protobuf/types/known/timestamppb"
+	durationpbPath  = "google.golang.org/protobuf/types/known/durationpb"
+	wrapperspbPath  = "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// wellKnownType описание соответствия типа Go well-known типу протобуфа
+type wellKnownType struct {
+	// kind вид соответствия для FieldMatchWellKnown
+	kind string
+	// goPkg, goName тип Go
+	goPkg  string
+	goName string
+	// wrapped тип значения обёртки, задаётся вместо goPkg и goName для обёрток wrapperspb: им соответствуют все
+	// типы Go с данным underlying-типом
+	wrapped types.Type
+	// pbPkg, pbName тип протобуфа
+	pbPkg  string
+	pbName string
+	// pbRef название импорта пакета протобуфа в генерируемом коде
+	pbRef string
+	// pbNew функция пакета протобуфа создающая значение из значения типа Go
+	pbNew string
+	// nonZero условие непустого значения типа Go, подставляемого вместо $0. Пустые значения конвертируются в nil,
+	// если условие не задано, то пустые значения сохраняются.
+	nonZero string
+	// asGo метод типа протобуфа возвращающий значение типа Go
+	asGo string
+	// checkValid значения протобуфа проверяются методом CheckValid
+	checkValid bool
+}
+
+var wellKnownTypes = []wellKnownType{
+	{
+		kind:       "timestamp",
+		goPkg:      "time",
+		goName:     "Time",
+		pbPkg:      timestamppbPath,
+		pbName:     "Timestamp",
+		pbRef:      "timestamppb",
+		pbNew:      "New",
+		nonZero:    "!$0.IsZero()",
+		asGo:       "AsTime",
+		checkValid: true,
+	},
+	{
+		kind:       "duration",
+		goPkg:      "time",
+		goName:     "Duration",
+		pbPkg:      durationpbPath,
+		pbName:     "Duration",
+		pbRef:      "durationpb",
+		pbNew:      "New",
+		nonZero:    "$0 != 0",
+		asGo:       "AsDuration",
+		checkValid: true,
+	},
+	wrapperType("double", "DoubleValue", "Double", types.Typ[types.Float64]),
+	wrapperType("float", "FloatValue", "Float", types.Typ[types.Float32]),
+	wrapperType("int64", "Int64Value", "Int64", types.Typ[types.Int64]),
+	wrapperType("uint64", "UInt64Value", "UInt64", types.Typ[types.Uint64]),
+	wrapperType("int32", "Int32Value", "Int32", types.Typ[types.Int32]),
+	wrapperType("uint32", "UInt32Value", "UInt32", types.Typ[types.Uint32]),
+	wrapperType("bool", "BoolValue", "Bool", types.Typ[types.Bool]),
+	wrapperType("string", "StringValue", "String", types.Typ[types.String]),
+	wrapperType("bytes", "BytesValue", "Bytes", types.NewSlice(types.Universe.Lookup("byte").Type())),
+}
+
+// wrapperType описание обёртки wrapperspb
+func wrapperType(kind, name, constructor string, wrapped types.Type) wellKnownType {
+	return wellKnownType{
+		kind:    kind,
+		wrapped: wrapped,
+		pbPkg:   wrapperspbPath,
+		pbName:  name,
+		pbRef:   "wrapperspb",
+		pbNew:   constructor,
+		asGo:    "GetValue",
+	}
+}
+
+// matchWellKnown сопоставление типов Go и соответствующих им well-known типов протобуфа. Указатели на данном этапе
+// уже сняты.
+func matchWellKnown(prim, sec types.Type) (*FieldMatchWellKnown, bool) {
+	for i := range wellKnownTypes {
+		wk := &wellKnownTypes[i]
+		switch {
+		case wk.matchesGo(prim) && isNamedType(sec, wk.pbPkg, wk.pbName):
+			return &FieldMatchWellKnown{
+				Kind: wk.kind,
+				wk:   wk,
+			}, true
+		case isNamedType(prim, wk.pbPkg, wk.pbName) && wk.matchesGo(sec):
+			return &FieldMatchWellKnown{
+				Kind:      wk.kind,
+				FromProto: true,
+				wk:        wk,
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// matchesGo проверка, что тип Go соответствует данному well-known типу
+func (wk *wellKnownType) matchesGo(t types.Type) bool {
+	if wk.wrapped == nil {
+		return isNamedType(t, wk.goPkg, wk.goName)
+	}
+
+	switch t.(type) {
+	case *types.Basic, *types.Named, *types.Slice:
+		return types.Identical(t.Underlying(), wk.wrapped)
+	default:
+		return false
+	}
+}
+
+// isNamedType проверка, что тип является данным именованным типом данного пакета
+func isNamedType(t types.Type, pkg, name string) bool {
+	v, ok := t.(*types.Named)
+	if !ok || v.Obj().Pkg() == nil {
+		return false
+	}
+
+	return v.Obj().Pkg().Path() == pkg && v.Obj().Name() == name
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func newTestNamed(pkg *types.Package, name string, underlying types.Type) *types.Named {
+	return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), underlying, nil)
+}
+
+func Test_matchWellKnown(t *testing.T) {
+	timePkg := types.NewPackage("time", "time")
+	timeType := newTestNamed(timePkg, "Time", types.NewStruct(nil, nil))
+	durationType := newTestNamed(timePkg, "Duration", types.Typ[types.Int64])
+	timestamp := newTestNamed(types.NewPackage(timestamppbPath, "timestamppb"), "Timestamp", types.NewStruct(nil, nil))
+	duration := newTestNamed(types.NewPackage(durationpbPath, "durationpb"), "Duration", types.NewStruct(nil, nil))
+	foreignTime := newTestNamed(types.NewPackage("example.com/time", "time"), "Time", types.NewStruct(nil, nil))
+	wrapperspb := types.NewPackage(wrapperspbPath, "wrapperspb")
+	stringValue := newTestNamed(wrapperspb, "StringValue", types.NewStruct(nil, nil))
+	int32Value := newTestNamed(wrapperspb, "Int32Value", types.NewStruct(nil, nil))
+	bytesValue := newTestNamed(wrapperspb, "BytesValue", types.NewStruct(nil, nil))
+	domain := types.NewPackage("example.com/domain", "domain")
+	name := newTestNamed(domain, "Name", types.Typ[types.String])
+	bytes := types.NewSlice(types.Typ[types.Byte])
+
+	tests := []struct {
+		name      string
+		prim      types.Type
+		sec       types.Type
+		wantKind  string
+		wantProto bool
+		wantOK    bool
+	}{
+		{
+			name:     "time-to-timestamp",
+			prim:     timeType,
+			sec:      timestamp,
+			wantKind: "timestamp",
+			wantOK:   true,
+		},
+		{
+			name:      "timestamp-to-time",
+			prim:      timestamp,
+			sec:       timeType,
+			wantKind:  "timestamp",
+			wantProto: true,
+			wantOK:    true,
+		},
+		{
+			name:     "duration-to-duration",
+			prim:     durationType,
+			sec:      duration,
+			wantKind: "duration",
+			wantOK:   true,
+		},
+		{
+			name:      "duration-from-proto",
+			prim:      duration,
+			sec:       durationType,
+			wantKind:  "duration",
+			wantProto: true,
+			wantOK:    true,
+		},
+		{
+			name:     "string-to-wrapper",
+			prim:     types.Typ[types.String],
+			sec:      stringValue,
+			wantKind: "string",
+			wantOK:   true,
+		},
+		{
+			name:      "wrapper-to-named-string",
+			prim:      stringValue,
+			sec:       name,
+			wantKind:  "string",
+			wantProto: true,
+			wantOK:    true,
+		},
+		{
+			name:     "bytes-to-wrapper",
+			prim:     bytes,
+			sec:      bytesValue,
+			wantKind: "bytes",
+			wantOK:   true,
+		},
+		{
+			name: "int64-to-int32-wrapper",
+			prim: types.Typ[types.Int64],
+			sec:  int32Value,
+		},
+		{
+			name: "string-slice-to-bytes-wrapper",
+			prim: types.NewSlice(types.Typ[types.String]),
+			sec:  bytesValue,
+		},
+		{
+			name: "time-to-duration",
+			prim: timeType,
+			sec:  duration,
+		},
+		{
+			name: "int64-to-duration",
+			prim: types.Typ[types.Int64],
+			sec:  duration,
+		},
+		{
+			name: "foreign-time",
+			prim: foreignTime,
+			sec:  timestamp,
+		},
+		{
+			name: "timestamp-to-timestamp",
+			prim: timestamp,
+			sec:  timestamp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchWellKnown(tt.prim, tt.sec)
+			if ok != tt.wantOK {
+				t.Fatalf("matchWellKnown() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			if got.Kind != tt.wantKind {
+				t.Errorf("matchWellKnown() Kind = %v, want %v", got.Kind, tt.wantKind)
+			}
+			if got.FromProto != tt.wantProto {
+				t.Errorf("matchWellKnown() FromProto = %v, want %v", got.FromProto, tt.wantProto)
+			}
+		})
+	}
+}
+
+func TestGenerator_wellKnownGenerated(t *testing.T) {
+	runGenerated(t, "wellknown", testdataPair("wellknown", "Event", "EventPB"))
+}
+
+func TestGenerator_wrappersGenerated(t *testing.T) {
+	runGenerated(t, "wrappers", testdataPair("wrappers", "Profile", "ProfilePB"))
+}
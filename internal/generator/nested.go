@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// nestedConversions реестр конвертаций пар структур общий для генераторов одного запуска. Через него находятся
+// уже известные конвертации вложенных структур, в том числе при циклических ссылках структур друг на друга.
+type nestedConversions struct {
+	gens    map[string]*Generator
+	queued  map[*Generator]struct{}
+	pending []*Generator
+	// outputs файлы перезаписываемые при генерации, объявленные в них функции не считаются функциями конвертации
+	outputs map[string]struct{}
+	// secFuncs пакеты secondary-структур по представлениям в названиях функций конвертации для primary-структуры
+	secFuncs map[string]string
+	// refs пути пакетов по именам импортов в файлах, генераторы вложенных структур пишут в файл родительского
+	refs map[*matiss.GoRenderer]map[string]string
+}
+
+func newNestedConversions() *nestedConversions {
+	return &nestedConversions{
+		gens:     map[string]*Generator{},
+		queued:   map[*Generator]struct{}{},
+		outputs:  map[string]struct{}{},
+		secFuncs: map[string]string{},
+		refs:     map[*matiss.GoRenderer]map[string]string{},
+	}
+}
+
+func nestedKey(prim, sec *types.Named) string {
+	return prim.String() + " " + sec.String()
+}
+
+// add регистрация конвертации пары структур генерируемой самостоятельно
+func (n *nestedConversions) add(g *Generator) {
+	n.gens[nestedKey(g.prim, g.sec)] = g
+	n.queued[g] = struct{}{}
+	g.secFunc = n.secFuncName(g.prim, g.sec)
+
+	position := g.fs.Position(g.prim.Obj().Pos())
+	n.outputs[filepath.Join(filepath.Dir(position.Filename), g.fileName())] = struct{}{}
+}
+
+// get генератор конвертаций вложенных структур, создаётся с настройками родительского генератора если ещё не известен.
+// Такой генератор используется всеми родительскими, поэтому генерирует оба направления независимо от их настроек:
+// иначе возможность сопоставления вложенных структур зависела бы от порядка пар.
+func (n *nestedConversions) get(parent *Generator, prim, sec *types.Named) *Generator {
+	key := nestedKey(prim, sec)
+	if g, ok := n.gens[key]; ok {
+		return g
+	}
+
+	g := &Generator{
+		prim:            prim,
+		sec:             sec,
+		strictTo:        parent.strictTo,
+		strictFrom:      parent.strictFrom,
+		fieldHooks:      parent.fieldHooks,
+		enumFallbackAll: parent.enumFallbackAll,
+		enumFallbacks:   parent.enumFallbacks,
+		enumTo:          parent.enumTo,
+		enumFrom:        parent.enumFrom,
+		enumToUsed:      parent.enumToUsed,
+		enumFromUsed:    parent.enumFromUsed,
+		checkNarrowing:  parent.checkNarrowing,
+		collectErrors:   parent.collectErrors,
+		errorsBackend:   parent.errorsBackend,
+		fs:              parent.fs,
+		secFunc:         n.secFuncName(prim, sec),
+		nested:          n,
+	}
+	n.gens[key] = g
+
+	return g
+}
+
+// secFuncName представление secondary-структуры в названиях функций конвертации: название структуры, для структур
+// из других пакетов с префиксом secpkg. Одноимённые структуры разных пакетов дополнительно различаются названием
+// пакета.
+func (n *nestedConversions) secFuncName(prim, sec *types.Named) string {
+	name := sec.Obj().Name()
+	if sec.Obj().Pkg().Path() != prim.Obj().Pkg().Path() {
+		name = "secpkg_" + name
+	}
+
+	qualified := "secpkg_" + sec.Obj().Pkg().Name() + "_" + sec.Obj().Name()
+	candidate := name
+	for i := 0; ; i++ {
+		key := prim.String() + " " + candidate
+		if path, ok := n.secFuncs[key]; !ok || path == sec.Obj().Pkg().Path() {
+			n.secFuncs[key] = sec.Obj().Pkg().Path()
+			return candidate
+		}
+
+		candidate = qualified
+		if i > 0 {
+			candidate = fmt.Sprintf("%s_%d", qualified, i)
+		}
+	}
+}
+
+// importRef имя импорта пакета path в файле r: prefix, если это имя ещё не занято другим пакетом, иначе prefix с
+// номером
+func (n *nestedConversions) importRef(r *matiss.GoRenderer, path string, prefix string) string {
+	refs, ok := n.refs[r]
+	if !ok {
+		refs = map[string]string{}
+		n.refs[r] = refs
+	}
+
+	ref := prefix
+	for i := 1; ; i++ {
+		if bound, ok := refs[ref]; !ok || bound == path {
+			break
+		}
+		ref = fmt.Sprintf("%s%d", prefix, i)
+	}
+
+	refs[ref] = path
+	r.Imports().Add(path).Ref(ref)
+	return ref
+}
+
+// require постановка конвертации вложенных структур в очередь генерации, если она ещё не генерировалась
+func (n *nestedConversions) require(g *Generator) {
+	if _, ok := n.queued[g]; ok {
+		return
+	}
+
+	n.queued[g] = struct{}{}
+	n.pending = append(n.pending, g)
+}
+
+// next очередная конвертация вложенных структур требующая генерации, nil если таких нет
+func (n *nestedConversions) next() *Generator {
+	if len(n.pending) == 0 {
+		return nil
+	}
+
+	g := n.pending[0]
+	n.pending = n.pending[1:]
+	return g
+}
+
+// nestedMatch сопоставление вложенных структур. Конвертации генерируются в пакете primary-структуры, поэтому
+// вложенная primary-структура должна находиться в нём же. Структуры сопоставляются если совпадают их названия либо
+// у них есть хотя бы одно общее публичное поле.
+func (g *Generator) nestedMatch(prim, sec types.Type) (*FieldMatchNested, bool) {
+	if g.nested == nil {
+		return nil, false
+	}
+
+	p, ok := prim.(*types.Named)
+	if !ok || !isStruct(p) || p.Obj().Pkg() == nil || p.Obj().Pkg().Path() != g.prim.Obj().Pkg().Path() {
+		return nil, false
+	}
+
+	s, ok := sec.(*types.Named)
+	if !ok || !isStruct(s) || s.Obj().Pkg() == nil {
+		return nil, false
+	}
+
+	if !s.Obj().Exported() && s.Obj().Pkg().Path() != g.prim.Obj().Pkg().Path() {
+		return nil, false
+	}
+
+	if p.Obj().Name() != s.Obj().Name() && !sharesFields(p, s) {
+		// произвольные структуры без общих полей парой не считаются
+		return nil, false
+	}
+
+	conv := g.nested.get(g, p, s)
+	if (conv.noTo && !g.noTo) || (conv.noFrom && !g.noFrom) {
+		// нужное направление конвертации отключено у самостоятельно генерируемой пары
+		return nil, false
+	}
+
+	return &FieldMatchNested{
+		Primary:   p.String(),
+		Secondary: s.String(),
+		conv:      conv,
+	}, true
+}
+
+// isOverwritten проверка, что объявление находится в файле перезаписываемом генерацией
+func (g *Generator) isOverwritten(pos token.Pos) bool {
+	if g.nested == nil {
+		return false
+	}
+
+	_, ok := g.nested.outputs[g.fs.Position(pos).Filename]
+	return ok
+}
+
+// call вызов конвертации вложенной структуры из значения src
+func (n *FieldMatchNested) call(r *matiss.GoRenderer, src string, srcType types.Type) string {
+	conv := n.conv
+	if !n.reflected {
+		if conv.method != "" {
+			return r.S(`$0.$1()`, src, conv.method)
+		}
+
+		return r.S(`$0($1)`, conv.toName(r), rightReference(src, srcType, types.NewPointer(conv.prim)))
+	}
+
+	if conv.fromMethod != "" {
+		return r.S(`$0.$1()`, src, conv.fromMethod)
+	}
+
+	return r.S(`$0($1)`, conv.fromName(r), rightReference(src, srcType, types.NewPointer(conv.sec)))
+}
+
+// sharesFields проверка наличия у структур публичного поля с совпадающим именем
+func sharesFields(prim, sec *types.Named) bool {
+	p := prim.Underlying().(*types.Struct)
+	s := sec.Underlying().(*types.Struct)
+	for i := 0; i < p.NumFields(); i++ {
+		f := p.Field(i)
+		if f.Exported() && lookupFieldByName(s, matiss.Underscored(f.Name())) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStruct(t *types.Named) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
@@ -0,0 +1,285 @@
+package generator
+
+import (
+	"go/ast"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+const nestedTestdata = "awesome-converter/internal/generator/testdata/nested"
+
+func newNestedTestGenerator(t *testing.T, opts ...Option) *Generator {
+	t.Helper()
+
+	gens, err := NewBatch([]Pair{
+		{
+			PrimaryPkg:    nestedTestdata + "/domain",
+			PrimaryName:   "Order",
+			SecondaryPkg:  nestedTestdata + "/pb",
+			SecondaryName: "Order",
+			Options:       opts,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewBatch() error = %v", err)
+	}
+
+	return gens[0]
+}
+
+// nestedDescription краткое описание сопоставления вложенных структур
+func nestedDescription(descr FieldMatchDescription) string {
+	switch v := descr.(type) {
+	case *FieldMatchNested:
+		return strings.TrimPrefix(v.Primary, nestedTestdata+"/") + " " + strings.TrimPrefix(v.Secondary, nestedTestdata+"/")
+	case *FieldMatchSlice:
+		return "[]" + nestedDescription(v.Elem)
+	case *FieldMatchMap:
+		return "map " + nestedDescription(v.Elem)
+	default:
+		return reflect.TypeOf(descr).Elem().Name()
+	}
+}
+
+func Test_nestedMatches(t *testing.T) {
+	g := newNestedTestGenerator(t)
+
+	matches, _ := g.getFieldsMatches(g.manual)
+	got := map[string]string{}
+	for _, m := range matches {
+		got[m.prim.Name()] = nestedDescription(m.descr)
+	}
+
+	want := map[string]string{
+		"ID":       "FieldMatchDirect",
+		"Customer": "domain.Customer pb.Customer",
+		"Items":    "[]domain.Item pb.Item",
+		"Labels":   "map domain.Label pb.Label",
+		"Parent":   "domain.Order pb.Order",
+		"Shipping": "domain.Address pb.Address",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getFieldsMatches() = %v, want %v", got, want)
+	}
+
+	// циклическая ссылка указывает на генератор основной пары
+	for _, m := range matches {
+		if m.prim.Name() != "Parent" {
+			continue
+		}
+
+		if conv := m.descr.(*FieldMatchNested).conv; conv != g {
+			t.Errorf("Parent is converted by %s and %s, want the generator of the main pair", conv.prim, conv.sec)
+		}
+	}
+}
+
+func Test_nestedDirectionsOrder(t *testing.T) {
+	order := Pair{
+		PrimaryPkg:    nestedTestdata + "/domain",
+		PrimaryName:   "Order",
+		SecondaryPkg:  nestedTestdata + "/pb",
+		SecondaryName: "Order",
+		Options:       []Option{WithoutFrom()},
+	}
+	customer := Pair{
+		PrimaryPkg:    nestedTestdata + "/domain",
+		PrimaryName:   "Customer",
+		SecondaryPkg:  nestedTestdata + "/pb",
+		SecondaryName: "Customer",
+		Options:       []Option{WithOutput("customer_convgen.go")},
+	}
+
+	// обе пары используют конвертацию domain.Address ↔ pb.Address, её сопоставление не должно зависеть от порядка пар
+	for _, pairs := range [][]Pair{{order, customer}, {customer, order}} {
+		gens, err := NewBatch(pairs)
+		if err != nil {
+			t.Fatalf("NewBatch() error = %v", err)
+		}
+
+		for _, g := range gens {
+			matches, _ := g.getFieldsMatches(g.manual)
+			for _, m := range matches {
+				if m.prim.Name() != "Address" && m.prim.Name() != "Shipping" {
+					continue
+				}
+
+				v, ok := m.descr.(*FieldMatchNested)
+				if !ok {
+					t.Errorf("%s.%s is not matched as nested structure: %s", g.prim, m.prim.Name(), m.descr)
+					continue
+				}
+				if v.conv.noTo || v.conv.noFrom {
+					t.Errorf("both directions of %s and %s must be generated", v.conv.prim, v.conv.sec)
+				}
+			}
+		}
+	}
+}
+
+func Test_nestedMatch(t *testing.T) {
+	g := newNestedTestGenerator(t)
+	domain := g.prim.Obj().Pkg().Scope()
+	pb := g.sec.Obj().Pkg().Scope()
+
+	tests := []struct {
+		name string
+		prim string
+		sec  string
+		want bool
+	}{
+		{
+			name: "same-name",
+			prim: "Label",
+			sec:  "Label",
+			want: true,
+		},
+		{
+			name: "shared-field",
+			prim: "Meta",
+			sec:  "Info",
+			want: true,
+		},
+		{
+			name: "no-shared-fields",
+			prim: "Meta",
+			sec:  "Address",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := g.nestedMatch(domain.Lookup(tt.prim).Type(), pb.Lookup(tt.sec).Type())
+			if got != tt.want {
+				t.Errorf("nestedMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nestedConversions_secFuncName(t *testing.T) {
+	g := newNestedTestGenerator(t)
+	domain := g.prim.Obj().Pkg().Scope()
+	pb := g.sec.Obj().Pkg().Scope()
+	geo := pb.Lookup("Customer").Type().Underlying().(*types.Struct).Field(1).Type()
+
+	pbAddress, ok := g.nestedMatch(domain.Lookup("Address").Type(), pb.Lookup("Address").Type())
+	if !ok {
+		t.Fatal("domain.Address and pb.Address are not matched")
+	}
+	geoAddress, ok := g.nestedMatch(domain.Lookup("Address").Type(), geo)
+	if !ok {
+		t.Fatal("domain.Address and geo.Address are not matched")
+	}
+
+	if got, want := pbAddress.conv.secFunc, "secpkg_Address"; got != want {
+		t.Errorf("secFunc of pb.Address = %v, want %v", got, want)
+	}
+	if got, want := geoAddress.conv.secFunc, "secpkg_geo_Address"; got != want {
+		t.Errorf("secFunc of geo.Address = %v, want %v", got, want)
+	}
+}
+
+func TestGenerator_Generate_nested(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(wd, "..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	g := newNestedTestGenerator(t, WithoutManualStubs())
+	prj, err := matiss.UpdateProject()
+	if err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	if err := g.Generate(prj); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tmp := t.TempDir()
+	if err := prj.Render(matiss.Directory(tmp)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// сгенерированный файл подкладывается в пакет primary-структуры и проверяется компилятором
+	overlay := map[string][]byte{}
+	err = filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		overlay[filepath.Join(wd, "..", "..", rel)] = content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("collect rendered files: %v", err)
+	}
+
+	pkgs, err := packages.Load(
+		&packages.Config{
+			Mode:    packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps,
+			Overlay: overlay,
+		},
+		nestedTestdata+"/domain",
+	)
+	if err != nil {
+		t.Fatalf("load generated package: %v", err)
+	}
+	for _, e := range pkgs[0].Errors {
+		t.Errorf("generated code: %v", e)
+	}
+
+	var funcs []string
+	paths := map[string]string{}
+	for _, f := range pkgs[0].Syntax {
+		for _, spec := range f.Imports {
+			if spec.Name == nil {
+				continue
+			}
+
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if prev, ok := paths[spec.Name.Name]; ok && prev != path {
+				t.Errorf("import name %s is used for both %s and %s", spec.Name.Name, prev, path)
+			}
+			paths[spec.Name.Name] = path
+		}
+
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && strings.Contains(fn.Name.Name, "Address") {
+				funcs = append(funcs, fn.Name.Name)
+			}
+		}
+	}
+
+	want := []string{
+		"AddressToSecpkgAddress",
+		"SecpkgAddressToAddress",
+		"AddressToSecpkgGeoAddress",
+		"SecpkgGeoAddressToAddress",
+	}
+	if !reflect.DeepEqual(funcs, want) {
+		t.Errorf("conversions of Address = %v, want %v", funcs, want)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// Option опция генератора
+type Option func(g *Generator)
+
+// WithManualMatches ручное сопоставление полей: ключи — имена полей primary-структуры, значения — имена полей
+// secondary-структуры. Сопоставленные таким образом поля проходят обычную проверку эквивалентности типов.
+func WithManualMatches(matches map[string]string) Option {
+	return func(g *Generator) {
+		if g.manual == nil {
+			g.manual = map[string]string{}
+			g.manualInput = map[string]string{}
+		}
+
+		for prim, sec := range matches {
+			g.manual[matiss.Underscored(prim)] = matiss.Underscored(sec)
+			g.manualInput[prim] = sec
+		}
+	}
+}
+
+// WithIgnoredFields исключение полей primary или secondary структур из конвертации. Для полей primary-структуры
+// это эквивалентно тегу conv:"-", поля secondary-структуры не считаются требующими сопоставления.
+func WithIgnoredFields(fields ...string) Option {
+	return func(g *Generator) {
+		if g.ignored == nil {
+			g.ignored = map[string]string{}
+		}
+
+		for _, field := range fields {
+			g.ignored[matiss.Underscored(field)] = field
+		}
+	}
+}
+
+// WithOutput задание имени файла для генерируемого кода в пакете primary-структуры
+func WithOutput(fileName string) Option {
+	return func(g *Generator) {
+		g.output = fileName
+	}
+}
+
+// WithStrict запрет генерации вызова ручной процедуры конвертации: при наличии несопоставленных полей генерация
+// завершается ошибкой. Задаётся отдельно для направлений primary → secondary (to) и secondary → primary (from).
+func WithStrict(to, from bool) Option {
+	return func(g *Generator) {
+		g.strictTo = g.strictTo || to
+		g.strictFrom = g.strictFrom || from
+	}
+}
+
+// WithFieldHooks генерация вызовов функций ручной конвертации отдельных несопоставленных полей вместо одной функции
+// ручной конвертации всей структуры
+func WithFieldHooks() Option {
+	return func(g *Generator) {
+		g.fieldHooks = true
+	}
+}
+
+// WithFromFunc задание названия функции конвертации secondary → primary вместо <Sec>To<Prim>
+func WithFromFunc(name string) Option {
+	return func(g *Generator) {
+		g.fromFunc = name
+	}
+}
+
+// WithFromMethod генерация конвертации secondary → primary методом secondary-структуры. Возможно только если
+// secondary-структура находится в одном пакете с primary.
+func WithFromMethod(name string) Option {
+	return func(g *Generator) {
+		g.fromMethod = name
+	}
+}
+
+// WithoutTo отключение генерации конвертации primary → secondary
+func WithoutTo() Option {
+	return func(g *Generator) {
+		g.noTo = true
+	}
+}
+
+// WithoutFrom отключение генерации конвертации secondary → primary
+func WithoutFrom() Option {
+	return func(g *Generator) {
+		g.noFrom = true
+	}
+}
+
+// WithEnumFallback поведение конвертации перечислений для значений не имеющих пары, по умолчанию возвращается ошибка
+func WithEnumFallback(fallback EnumFallback) Option {
+	return func(g *Generator) {
+		g.enumFallbackAll = fallback
+	}
+}
+
+// WithEnumFallbackFor поведение для значений не имеющих пары при конвертации в перечисление с данным названием
+// типа, с путём пакета (<pkg-path>.<name>) либо без. Имеет приоритет над WithEnumFallback.
+func WithEnumFallbackFor(enum string, fallback EnumFallback) Option {
+	return func(g *Generator) {
+		if g.enumFallbacks == nil {
+			g.enumFallbacks = map[string]EnumFallback{}
+		}
+
+		g.enumFallbacks[enum] = fallback
+	}
+}
+
+// WithEnumMapping таблицы сопоставления констант перечислений для конвертаций primary → secondary (to) и
+// secondary → primary (from): ключи — названия констант исходного перечисления, значения — названия констант
+// перечисления-приёмника. Несколько констант могут сопоставляться одной.
+func WithEnumMapping(to, from map[string]string) Option {
+	return func(g *Generator) {
+		if g.enumTo == nil {
+			g.enumTo = map[string]string{}
+		}
+		if g.enumFrom == nil {
+			g.enumFrom = map[string]string{}
+		}
+		if g.enumToUsed == nil {
+			g.enumToUsed = map[string]struct{}{}
+			g.enumFromUsed = map[string]struct{}{}
+		}
+
+		for k, v := range to {
+			g.enumTo[k] = v
+		}
+		for k, v := range from {
+			g.enumFrom[k] = v
+		}
+	}
+}
+
+// WithNarrowingChecks генерация проверок сужающих приведений числовых типов: выход за диапазон значений, потеря
+// дробной части и точности, NaN для целых типов приводят к ошибке конвертации
+func WithNarrowingChecks() Option {
+	return func(g *Generator) {
+		g.checkNarrowing = true
+	}
+}
+
+// WithCollectErrors сбор ошибок конвертации всех полей: конвертация не прерывается на первой ошибке, а возвращает
+// все ошибки списком convgen.Errors, с библиотеками ошибок pkg и std — объединёнными errors.Join
+func WithCollectErrors() Option {
+	return func(g *Generator) {
+		g.collectErrors = true
+	}
+}
+
+// WithoutManualStubs заготовки отсутствующих функций ручной конвертации не генерируются, вместо этого генерация
+// завершается ошибкой с их списком. Используется когда файлы проекта не должны изменяться, например при проверке
+// актуальности сгенерированного кода.
+func WithoutManualStubs() Option {
+	return func(g *Generator) {
+		g.noStubs = true
+	}
+}
+
+// WithErrorsBackend библиотека ошибок используемая сгенерированным кодом, см. ParseErrorsBackend
+func WithErrorsBackend(backend ErrorsBackend) Option {
+	return func(g *Generator) {
+		g.errorsBackend = backend
+	}
+}
@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+)
+
+// strictError ошибка со списком полей требующих ручной конвертации в направлениях запрещающих её: primary →
+// secondary (to) и secondary → primary (from), см. manualFields
+func (g *Generator) strictError(to, from []*types.Var) error {
+	var lines []string
+	for _, f := range to {
+		lines = append(lines, g.strictErrorLine(f))
+	}
+	for _, f := range from {
+		lines = append(lines, g.strictErrorLine(f))
+	}
+
+	return errors.Newf(
+		"strict mode: unmatched fields between %s and %s:\n%s",
+		g.prim,
+		g.sec,
+		strings.Join(lines, "\n"),
+	)
+}
+
+func (g *Generator) strictErrorLine(f *types.Var) string {
+	side := "secondary"
+	prim := g.prim.Underlying().(*types.Struct)
+	for i := 0; i < prim.NumFields(); i++ {
+		if prim.Field(i) == f {
+			side = "primary"
+			break
+		}
+	}
+
+	return fmt.Sprintf("    %s: %s field %s (%s)", g.fs.Position(f.Pos()), side, f.Name(), f.Type())
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerator_strictDirections(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantErr    []string
+		wantNot    []string
+		wantFunc   []string
+		wantNoFunc []string
+	}{
+		{
+			name:    "strict-to",
+			opts:    []Option{WithStrict(true, false)},
+			wantErr: []string{"primary field Extra"},
+			wantNot: []string{"Other"},
+		},
+		{
+			name:    "strict-from",
+			opts:    []Option{WithStrict(false, true)},
+			wantErr: []string{"secondary field Other"},
+			wantNot: []string{"Extra"},
+		},
+		{
+			name:    "strict-both",
+			opts:    []Option{WithStrict(true, true)},
+			wantErr: []string{"primary field Extra", "secondary field Other"},
+		},
+		{
+			name:    "strict-to-field-hooks",
+			opts:    []Option{WithStrict(true, false), WithFieldHooks()},
+			wantErr: []string{"secondary field Other"},
+			wantNot: []string{"Extra"},
+		},
+		{
+			name:    "strict-from-field-hooks",
+			opts:    []Option{WithStrict(false, true), WithFieldHooks()},
+			wantErr: []string{"primary field Extra"},
+			wantNot: []string{"Other"},
+		},
+		{
+			name:       "strict-to-without-to",
+			opts:       []Option{WithStrict(true, false), WithoutTo()},
+			wantFunc:   []string{"func ItemPBToItem(", "func manualItemPBToItem("},
+			wantNoFunc: []string{"func ItemToItemPB("},
+		},
+		{
+			name:       "strict-from-without-from",
+			opts:       []Option{WithStrict(false, true), WithoutFrom()},
+			wantFunc:   []string{"func ItemToItemPB(", "func manualItemToItemPB("},
+			wantNoFunc: []string{"func ItemPBToItem("},
+		},
+		{
+			name:    "strict-both-without-to",
+			opts:    []Option{WithStrict(true, true), WithoutTo()},
+			wantErr: []string{"secondary field Other"},
+			wantNot: []string{"Extra"},
+		},
+		{
+			name:    "strict-both-without-from",
+			opts:    []Option{WithStrict(true, true), WithoutFrom()},
+			wantErr: []string{"primary field Extra"},
+			wantNot: []string{"Other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, _, err := generateTestdata(t, testdataPair("strict", "Item", "ItemPB", tt.opts...))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				var code strings.Builder
+				for _, content := range files {
+					code.WriteString(content)
+				}
+				for _, want := range tt.wantFunc {
+					if !strings.Contains(code.String(), want) {
+						t.Errorf("generated code must contain %q:\n%s", want, code.String())
+					}
+				}
+				for _, not := range tt.wantNoFunc {
+					if strings.Contains(code.String(), not) {
+						t.Errorf("generated code must not contain %q:\n%s", not, code.String())
+					}
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("strict mode error expected")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error must mention %q, got: %v", want, err)
+				}
+			}
+			for _, not := range tt.wantNot {
+				if strings.Contains(err.Error(), not) {
+					t.Errorf("error must not mention %q, got: %v", not, err)
+				}
+			}
+		})
+	}
+}
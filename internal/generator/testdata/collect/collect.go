@@ -0,0 +1,17 @@
+// Package collect структуры для тестов сбора ошибок конвертации всех полей
+package collect
+
+// Order primary-структура, Email и Priority соответствуют ветвям oneof-а secondary-структуры
+type Order struct {
+	ID       int64
+	Name     string
+	Address  Address
+	Email    *string
+	Priority *int64
+}
+
+// Address вложенная primary-структура
+type Address struct {
+	Zip  int64
+	City string
+}
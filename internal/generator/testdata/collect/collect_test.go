@@ -0,0 +1,56 @@
+package collect
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"awesome-converter/convgen"
+)
+
+func TestOrderCollectErrors(t *testing.T) {
+	priority := int64(math.MaxInt32 + 1)
+	_, err := OrderToSecpkgOrder(&Order{
+		ID:       math.MaxInt32 + 1,
+		Name:     "order",
+		Address:  Address{Zip: math.MinInt32 - 1, City: "city"},
+		Priority: &priority,
+	})
+
+	var errs convgen.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("errors of all fields must be collected, got %v", err)
+	}
+
+	var paths []string
+	for _, err := range errs {
+		var convErr *convgen.ConversionError
+		if !errors.As(err, &convErr) {
+			t.Fatalf("unexpected error %v", err)
+		}
+		paths = append(paths, convErr.Path)
+	}
+	if want := []string{"ID", "Address.Zip", "Priority"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("collected errors paths = %v, want %v", paths, want)
+	}
+}
+
+func TestOrderCollectNoErrors(t *testing.T) {
+	email := "user@example.com"
+	pb, err := OrderToSecpkgOrder(&Order{ID: 1, Name: "order", Address: Address{Zip: 2}, Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.ID != 1 || pb.Name != "order" || pb.Address.Zip != 2 || pb.GetEmail() != email {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := SecpkgOrderToOrder(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.ID != 1 || back.Address.Zip != 2 || back.Email == nil || *back.Email != email {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
@@ -0,0 +1,66 @@
+// Package pb структуры в виде сгенерированных protoc-gen-go для тестов сбора ошибок конвертации
+package pb
+
+// Order secondary-структура с oneof-ом
+type Order struct {
+	ID      int32
+	Name    string
+	Address *Address
+	Contact isOrder_Contact
+}
+
+type isOrder_Contact interface {
+	isOrder_Contact()
+}
+
+// Order_Email ветвь oneof-а
+type Order_Email struct {
+	Email string
+}
+
+func (*Order_Email) isOrder_Contact() {}
+
+// Order_Priority ветвь oneof-а
+type Order_Priority struct {
+	Priority int32
+}
+
+func (*Order_Priority) isOrder_Contact() {}
+
+// GetContact геттер oneof-а
+func (o *Order) GetContact() isOrder_Contact {
+	if o != nil {
+		return o.Contact
+	}
+	return nil
+}
+
+// GetEmail геттер ветви oneof-а
+func (o *Order) GetEmail() string {
+	if v, ok := o.GetContact().(*Order_Email); ok {
+		return v.Email
+	}
+	return ""
+}
+
+// GetPriority геттер ветви oneof-а
+func (o *Order) GetPriority() int32 {
+	if v, ok := o.GetContact().(*Order_Priority); ok {
+		return v.Priority
+	}
+	return 0
+}
+
+// Address вложенная secondary-структура
+type Address struct {
+	Zip  int32
+	City string
+}
+
+// GetCity геттер поля
+func (a *Address) GetCity() string {
+	if a != nil {
+		return a.City
+	}
+	return ""
+}
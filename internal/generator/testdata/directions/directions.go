@@ -0,0 +1,14 @@
+// Package directions структуры для тестов названий и отключения направлений конвертации
+package directions
+
+// Point primary-структура
+type Point struct {
+	X int
+	Y int
+}
+
+// PointPB secondary-структура
+type PointPB struct {
+	X int
+	Y int
+}
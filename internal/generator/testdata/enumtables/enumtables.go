@@ -0,0 +1,31 @@
+// Package enumtables структуры для тестов конвертации перечислений по таблицам сопоставления значений
+package enumtables
+
+// Kind перечисление primary-структуры
+type Kind int
+
+// Значения Kind
+const (
+	KindPrimary Kind = iota + 1
+	KindBackup
+	KindLegacy
+)
+
+// KindPB перечисление secondary-структуры
+type KindPB int32
+
+// Значения KindPB
+const (
+	KindPBMain KindPB = iota + 1
+	KindPBReserve
+)
+
+// Storage primary-структура
+type Storage struct {
+	Kind Kind
+}
+
+// StoragePB secondary-структура
+type StoragePB struct {
+	Kind KindPB
+}
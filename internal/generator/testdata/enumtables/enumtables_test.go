@@ -0,0 +1,34 @@
+package enumtables
+
+import "testing"
+
+func TestStorageTables(t *testing.T) {
+	to := map[Kind]KindPB{
+		KindPrimary: KindPBMain,
+		KindBackup:  KindPBReserve,
+		KindLegacy:  KindPBMain,
+	}
+	for kind, want := range to {
+		pb, err := StorageToStoragePB(&Storage{Kind: kind})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pb.Kind != want {
+			t.Errorf("%v must be converted into %v, got %v", kind, want, pb.Kind)
+		}
+	}
+
+	from := map[KindPB]Kind{
+		KindPBMain:    KindPrimary,
+		KindPBReserve: KindBackup,
+	}
+	for kind, want := range from {
+		back, err := StoragePBToStorage(&StoragePB{Kind: kind})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.Kind != want {
+			t.Errorf("%v must be converted into %v, got %v", kind, want, back.Kind)
+		}
+	}
+}
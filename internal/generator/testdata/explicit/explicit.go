@@ -0,0 +1,90 @@
+// Package explicit структуры для тестов приоритета явных функций преобразования над встроенными сопоставлениями
+package explicit
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Money сумма в рублях, в протобуфе передаётся в копейках
+type Money int64
+
+// MoneyToProto явное преобразование вместо встроенного сопоставления с обёрткой
+func MoneyToProto(m Money) *wrapperspb.Int64Value {
+	return wrapperspb.Int64(int64(m) * 100)
+}
+
+// MoneyFromProto явное преобразование из обёртки
+func MoneyFromProto(v *wrapperspb.Int64Value) Money {
+	return Money(v.GetValue() / 100)
+}
+
+// Code код с текстовым представлением в нижнем регистре
+type Code struct {
+	Value string
+}
+
+// MarshalText для реализации encoding.TextMarshaler
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(c.Value)), nil
+}
+
+// UnmarshalText для реализации encoding.TextUnmarshaler
+func (c *Code) UnmarshalText(text []byte) error {
+	c.Value = strings.ToLower(string(text))
+	return nil
+}
+
+// CodeToString явное преобразование в строку в верхнем регистре
+func CodeToString(c Code) string {
+	return strings.ToUpper(c.Value)
+}
+
+// CodeFromString явное преобразование из строки
+func CodeFromString(s string) Code {
+	return Code{Value: strings.ToUpper(s)}
+}
+
+// TicketID UUID объявленный рядом со структурами, его String и Parse считаются явными функциями преобразования
+type TicketID [16]byte
+
+// String для реализации fmt.Stringer
+func (id TicketID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Parse разбор идентификатора, пустая строка не является идентификатором
+func Parse(s string) (TicketID, error) {
+	var id TicketID
+	if s == "" {
+		return id, errors.New("empty ticket id")
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return id, err
+	}
+	if len(b) != len(id) {
+		return id, errors.New("invalid ticket id length")
+	}
+	copy(id[:], b)
+
+	return id, nil
+}
+
+// Invoice primary-структура
+type Invoice struct {
+	Total  Money
+	Code   Code
+	Ticket TicketID
+}
+
+// InvoicePB secondary-структура
+type InvoicePB struct {
+	Total  *wrapperspb.Int64Value
+	Code   string
+	Ticket string
+}
@@ -0,0 +1,40 @@
+package explicit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInvoiceConversions(t *testing.T) {
+	ticket := TicketID{1, 2, 3}
+	pb, err := InvoiceToInvoicePB(&Invoice{Total: 5, Code: Code{Value: "ab"}, Ticket: ticket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Total.GetValue() != 500 || pb.Code != "AB" || pb.Ticket != ticket.String() {
+		t.Fatalf("explicit conversions must be used, got %v", pb)
+	}
+
+	back, err := InvoicePBToInvoice(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Total != 5 || back.Code.Value != "AB" || back.Ticket != ticket {
+		t.Fatalf("explicit back conversions must be used, got %v", back)
+	}
+}
+
+func TestInvoiceZeroTicket(t *testing.T) {
+	// встроенное сопоставление UUID преобразует нулевое значение в пустую строку и обратно, явные функции — нет
+	pb, err := InvoiceToInvoicePB(&Invoice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Ticket != strings.Repeat("0", 32) {
+		t.Fatalf("zero ticket must be converted by String, got %q", pb.Ticket)
+	}
+
+	if _, err := InvoicePBToInvoice(&InvoicePB{}); err == nil {
+		t.Fatal("empty ticket must be rejected by Parse")
+	}
+}
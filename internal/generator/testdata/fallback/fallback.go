@@ -0,0 +1,43 @@
+// Package fallback структуры для тестов конвертации значений перечислений не имеющих пары
+package fallback
+
+// Shade перечисление primary-структуры
+type Shade int
+
+// Значения Shade, ShadeNeon не имеет пары в Tone
+const (
+	ShadeUnknown Shade = iota
+	ShadeLight
+	ShadeDark
+	ShadeNeon
+)
+
+// Tone перечисление secondary-структур
+type Tone int
+
+// Значения Tone
+const (
+	ToneUnknown Tone = iota
+	ToneLight
+	ToneDark
+)
+
+// Lamp primary-структура
+type Lamp struct {
+	Shade Shade
+}
+
+// LampDefault secondary-структура с конвертацией в значение по умолчанию
+type LampDefault struct {
+	Shade Tone
+}
+
+// LampRaw secondary-структура с приведением значения как есть
+type LampRaw struct {
+	Shade Tone
+}
+
+// LampError secondary-структура с ошибкой конвертации
+type LampError struct {
+	Shade Tone
+}
@@ -0,0 +1,43 @@
+package fallback
+
+import "testing"
+
+func TestLampFallbacks(t *testing.T) {
+	def, err := LampToLampDefault(&Lamp{Shade: ShadeNeon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Shade != ToneUnknown {
+		t.Errorf("unknown value must be converted into default one, got %v", def.Shade)
+	}
+
+	raw, err := LampToLampRaw(&Lamp{Shade: ShadeNeon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.Shade != Tone(ShadeNeon) {
+		t.Errorf("unknown value must be converted as is, got %v", raw.Shade)
+	}
+
+	if _, err := LampToLampError(&Lamp{Shade: ShadeNeon}); err == nil {
+		t.Error("unknown value must not be converted")
+	}
+}
+
+func TestLampKnownValues(t *testing.T) {
+	def, err := LampToLampDefault(&Lamp{Shade: ShadeDark})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Shade != ToneDark {
+		t.Errorf("unexpected conversion result %v", def.Shade)
+	}
+
+	back, err := LampErrorToLamp(&LampError{Shade: ToneLight})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Shade != ShadeLight {
+		t.Errorf("unexpected back conversion result %v", back.Shade)
+	}
+}
@@ -0,0 +1,35 @@
+// Package fieldhooks структуры для тестов ручной конвертации отдельных полей
+package fieldhooks
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Order primary-структура, поле Quantity не имеет пары
+type Order struct {
+	ID       string
+	Quantity int
+}
+
+// OrderPB secondary-структура, поле Label не имеет пары
+type OrderPB struct {
+	ID    string
+	Label string
+}
+
+func convertOrderToOrderPBLabel(x *Order) (string, error) {
+	if x.Quantity < 0 {
+		return "", errors.New("negative quantity")
+	}
+
+	return x.ID + ":" + strconv.Itoa(x.Quantity), nil
+}
+
+func convertOrderPBToOrderQuantity(x *OrderPB) (int, error) {
+	if x.Label == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(x.Label[len(x.ID)+1:])
+}
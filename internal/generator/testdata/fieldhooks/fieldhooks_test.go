@@ -0,0 +1,30 @@
+package fieldhooks
+
+import "testing"
+
+func TestOrderFieldHooks(t *testing.T) {
+	pb, err := OrderToOrderPB(&Order{ID: "order", Quantity: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.ID != "order" || pb.Label != "order:3" {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := OrderPBToOrder(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.ID != "order" || back.Quantity != 3 {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestOrderFieldHookErrors(t *testing.T) {
+	if _, err := OrderToOrderPB(&Order{ID: "order", Quantity: -1}); err == nil {
+		t.Error("error of primary → secondary field hook must be returned")
+	}
+	if _, err := OrderPBToOrder(&OrderPB{ID: "order", Label: "order:many"}); err == nil {
+		t.Error("error of secondary → primary field hook must be returned")
+	}
+}
@@ -0,0 +1,28 @@
+// Package id идентификаторы регионов для тестов конвертации ключей словарей
+package id
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// RegionID идентификатор региона, эквивалентен строке как UUID
+type RegionID [16]byte
+
+// String представление идентификатора в виде строки из 32 шестнадцатеричных цифр
+func (r RegionID) String() string {
+	return hex.EncodeToString(r[:])
+}
+
+// Parse разбор идентификатора из строки
+func Parse(s string) (RegionID, error) {
+	var r RegionID
+	if hex.DecodedLen(len(s)) != len(r) {
+		return r, fmt.Errorf("invalid region id length %d", len(s))
+	}
+	if _, err := hex.Decode(r[:], []byte(s)); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
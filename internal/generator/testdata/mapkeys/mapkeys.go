@@ -0,0 +1,34 @@
+// Package mapkeys структуры для тестов конвертации ключей словарей
+package mapkeys
+
+import "awesome-converter/internal/generator/testdata/mapkeys/id"
+
+// Kind перечисление primary-структуры
+type Kind int
+
+// Значения Kind
+const (
+	KindPrimary Kind = iota + 1
+	KindBackup
+)
+
+// KindPB перечисление secondary-структуры
+type KindPB int32
+
+// Значения KindPB
+const (
+	KindPBMain KindPB = iota + 1
+	KindPBReserve
+)
+
+// Zone primary-структура
+type Zone struct {
+	Regions map[id.RegionID]string
+	Kinds   map[Kind]string
+}
+
+// ZonePB secondary-структура
+type ZonePB struct {
+	Regions map[string]string
+	Kinds   map[KindPB]string
+}
@@ -0,0 +1,51 @@
+package mapkeys
+
+import (
+	"strings"
+	"testing"
+
+	"awesome-converter/internal/generator/testdata/mapkeys/id"
+)
+
+func TestZoneConversions(t *testing.T) {
+	region := id.RegionID{1, 2, 3}
+	pb, err := ZoneToZonePB(&Zone{
+		Regions: map[id.RegionID]string{region: "eu"},
+		Kinds:   map[Kind]string{KindBackup: "backup"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pb.Regions) != 1 || pb.Regions[region.String()] != "eu" {
+		t.Fatalf("unexpected regions conversion result %v", pb.Regions)
+	}
+	if len(pb.Kinds) != 1 || pb.Kinds[KindPBMain] != "backup" {
+		t.Fatalf("unexpected kinds conversion result %v", pb.Kinds)
+	}
+
+	back, err := ZonePBToZone(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Regions) != 1 || back.Regions[region] != "eu" {
+		t.Fatalf("unexpected regions back conversion result %v", back.Regions)
+	}
+	if len(back.Kinds) != 1 || back.Kinds[KindPrimary] != "backup" {
+		t.Fatalf("unexpected kinds back conversion result %v", back.Kinds)
+	}
+}
+
+func TestZoneInvalidKey(t *testing.T) {
+	_, err := ZonePBToZone(&ZonePB{Regions: map[string]string{"bad": "eu"}})
+	if err == nil || !strings.Contains(err.Error(), `Regions["bad"]`) {
+		t.Errorf("conversion error = %v, must mention the invalid key", err)
+	}
+}
+
+func TestZoneDuplicateKey(t *testing.T) {
+	// обе константы сопоставлены KindPBMain
+	_, err := ZoneToZonePB(&Zone{Kinds: map[Kind]string{KindPrimary: "primary", KindBackup: "backup"}})
+	if err == nil || !strings.Contains(err.Error(), "duplicate key") {
+		t.Errorf("conversion error = %v, must report duplicate key", err)
+	}
+}
@@ -0,0 +1,18 @@
+// Package narrowing структуры для тестов проверок сужающих приведений числовых типов
+package narrowing
+
+// Sample primary-структура
+type Sample struct {
+	Count int64
+	Size  int
+	Ratio float64
+	Scale float64
+}
+
+// SamplePB secondary-структура с более узкими типами полей
+type SamplePB struct {
+	Count int32
+	Size  uint
+	Ratio int32
+	Scale float32
+}
@@ -0,0 +1,93 @@
+package narrowing
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSampleRepresentable(t *testing.T) {
+	pb, err := SampleToSamplePB(&Sample{Count: -5, Size: 3, Ratio: -2, Scale: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Count != -5 || pb.Size != 3 || pb.Ratio != -2 || pb.Scale != 0.5 {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := SamplePBToSample(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Count != -5 || back.Size != 3 || back.Ratio != -2 || back.Scale != 0.5 {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestSampleNotRepresentable(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample Sample
+		field  string
+	}{
+		{
+			name:   "int64-overflow",
+			sample: Sample{Count: math.MaxInt32 + 1},
+			field:  "Count",
+		},
+		{
+			name:   "negative-to-uint",
+			sample: Sample{Size: -1},
+			field:  "Size",
+		},
+		{
+			name:   "lost-fraction",
+			sample: Sample{Ratio: 1.5},
+			field:  "Ratio",
+		},
+		{
+			name:   "nan-to-int",
+			sample: Sample{Ratio: math.NaN()},
+			field:  "Ratio",
+		},
+		{
+			name:   "lost-precision",
+			sample: Sample{Scale: 0.1},
+			field:  "Scale",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SampleToSamplePB(&tt.sample)
+			if err == nil || !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("conversion error = %v, must mention %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestSampleFloatSpecialValues(t *testing.T) {
+	// NaN и бесконечности представимы в float32
+	pb, err := SampleToSamplePB(&Sample{Scale: math.NaN()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(float64(pb.Scale)) {
+		t.Errorf("NaN must be preserved, got %v", pb.Scale)
+	}
+
+	pb, err = SampleToSamplePB(&Sample{Scale: math.Inf(-1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(float64(pb.Scale), -1) {
+		t.Errorf("infinity must be preserved, got %v", pb.Scale)
+	}
+}
+
+func TestSampleBackOverflow(t *testing.T) {
+	_, err := SamplePBToSample(&SamplePB{Size: math.MaxUint64})
+	if err == nil || !strings.Contains(err.Error(), "Size") {
+		t.Errorf("conversion error = %v, must mention Size", err)
+	}
+}
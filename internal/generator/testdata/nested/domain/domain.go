@@ -0,0 +1,34 @@
+// Package domain primary-структуры для тестов конвертации вложенных структур
+package domain
+
+type Order struct {
+	ID       string
+	Customer *Customer
+	Items    []Item
+	Labels   map[string]*Label
+	Parent   *Order
+	Shipping Address
+}
+
+type Customer struct {
+	Name      string
+	Address   Address
+	LastOrder *Order
+}
+
+type Item struct {
+	SKU   string
+	Count int64
+}
+
+type Label struct {
+	Value string
+}
+
+type Address struct {
+	City string
+}
+
+type Meta struct {
+	Author string
+}
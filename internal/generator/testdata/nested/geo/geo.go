@@ -0,0 +1,6 @@
+// Package geo secondary-структура одноимённая структуре пакета pb для тестов конвертации вложенных структур
+package geo
+
+type Address struct {
+	City string
+}
@@ -0,0 +1,37 @@
+// Package pb secondary-структуры для тестов конвертации вложенных структур
+package pb
+
+import "awesome-converter/internal/generator/testdata/nested/geo"
+
+type Order struct {
+	ID       string
+	Customer *Customer
+	Items    []*Item
+	Labels   map[string]Label
+	Parent   *Order
+	Shipping *Address
+}
+
+type Customer struct {
+	Name      string
+	Address   geo.Address
+	LastOrder *Order
+}
+
+type Item struct {
+	SKU   string
+	Count int32
+}
+
+type Label struct {
+	Value string
+}
+
+type Address struct {
+	City string
+}
+
+type Info struct {
+	Author string
+	Source string
+}
@@ -0,0 +1,51 @@
+// Package protoenum структуры для тестов конвертации перечислений протобуфа через словари названий и значений
+package protoenum
+
+// Status перечисление-строка со значениями равными названиям значений протобуфа
+type Status string
+
+// Значения Status
+const (
+	StatusUnspecified Status = "STATUS_UNSPECIFIED"
+	StatusActive      Status = "STATUS_ACTIVE"
+	StatusBlocked     Status = "STATUS_BLOCKED"
+)
+
+// StatusPB перечисление в виде сгенерированного protoc-gen-go
+type StatusPB int32
+
+// Значения StatusPB
+const (
+	StatusPB_STATUS_UNSPECIFIED StatusPB = 0
+	StatusPB_STATUS_ACTIVE      StatusPB = 1
+	StatusPB_STATUS_BLOCKED     StatusPB = 2
+)
+
+// Словари названий и значений StatusPB
+var (
+	StatusPB_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_ACTIVE",
+		2: "STATUS_BLOCKED",
+	}
+	StatusPB_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_ACTIVE":      1,
+		"STATUS_BLOCKED":     2,
+	}
+)
+
+// Account primary-структура
+type Account struct {
+	Status Status
+}
+
+// AccountPB secondary-структура с ошибкой конвертации значений не имеющих пары
+type AccountPB struct {
+	Status StatusPB
+}
+
+// AccountDefault secondary-структура с конвертацией значений не имеющих пары в значение по умолчанию
+type AccountDefault struct {
+	Status StatusPB
+}
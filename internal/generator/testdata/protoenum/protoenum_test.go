@@ -0,0 +1,51 @@
+package protoenum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccountKnownValues(t *testing.T) {
+	pb, err := AccountToAccountPB(&Account{Status: StatusBlocked})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Status != StatusPB_STATUS_BLOCKED {
+		t.Fatalf("unexpected conversion result %v", pb.Status)
+	}
+
+	back, err := AccountPBToAccount(&AccountPB{Status: StatusPB_STATUS_ACTIVE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Status != StatusActive {
+		t.Fatalf("unexpected back conversion result %v", back.Status)
+	}
+}
+
+func TestAccountUnknownValues(t *testing.T) {
+	_, err := AccountToAccountPB(&Account{Status: "STATUS_DELETED"})
+	if err == nil || !strings.Contains(err.Error(), "Status") {
+		t.Errorf("unknown string value must not be converted, got error %v", err)
+	}
+
+	_, err = AccountPBToAccount(&AccountPB{Status: 7})
+	if err == nil || !strings.Contains(err.Error(), "Status") {
+		t.Errorf("unknown proto value must not be converted, got error %v", err)
+	}
+}
+
+func TestAccountFallback(t *testing.T) {
+	def, err := AccountToAccountDefault(&Account{Status: "STATUS_DELETED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Status != StatusPB_STATUS_UNSPECIFIED {
+		t.Errorf("unknown value must be converted into default one, got %v", def.Status)
+	}
+
+	// значение по умолчанию задано только для StatusPB
+	if _, err := AccountDefaultToAccount(&AccountDefault{Status: 7}); err == nil {
+		t.Error("unknown proto value must not be converted")
+	}
+}
@@ -0,0 +1,16 @@
+// Package strict структуры с несопоставленными полями в обеих структурах для тестов строгого режима
+package strict
+
+// Item primary-структура, поле Extra не имеет пары
+type Item struct {
+	ID    string
+	Name  string
+	Extra string
+}
+
+// ItemPB secondary-структура, поле Other не имеет пары
+type ItemPB struct {
+	ID    string
+	Name  string
+	Other string
+}
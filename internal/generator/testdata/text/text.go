@@ -0,0 +1,37 @@
+// Package text структуры для тестов конвертации типов с текстовым представлением
+package text
+
+import "fmt"
+
+// Color цвет с текстовым представлением вида #rrggbb
+type Color struct {
+	R, G, B uint8
+}
+
+// MarshalText для реализации encoding.TextMarshaler
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)), nil
+}
+
+// UnmarshalText для реализации encoding.TextUnmarshaler
+func (c *Color) UnmarshalText(text []byte) error {
+	if _, err := fmt.Sscanf(string(text), "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return fmt.Errorf("invalid color %q: %w", text, err)
+	}
+
+	return nil
+}
+
+// Palette primary-структура
+type Palette struct {
+	Main   Color
+	Accent *Color
+	Raw    Color
+}
+
+// PalettePB secondary-структура
+type PalettePB struct {
+	Main   string
+	Accent string
+	Raw    []byte
+}
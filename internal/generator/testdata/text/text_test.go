@@ -0,0 +1,41 @@
+package text
+
+import "testing"
+
+func TestPaletteConversions(t *testing.T) {
+	accent := Color{R: 0xff}
+	pb, err := PaletteToPalettePB(&Palette{Main: Color{G: 0x10}, Accent: &accent, Raw: Color{B: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Main != "#001000" || pb.Accent != "#ff0000" || string(pb.Raw) != "#000001" {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := PalettePBToPalette(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Main != (Color{G: 0x10}) || back.Accent == nil || *back.Accent != accent || back.Raw != (Color{B: 1}) {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestPaletteEmptyText(t *testing.T) {
+	back, err := PalettePBToPalette(&PalettePB{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Main != (Color{}) || back.Accent != nil || back.Raw != (Color{}) {
+		t.Fatalf("empty text must be converted into zero values, got %v", back)
+	}
+}
+
+func TestPaletteInvalidText(t *testing.T) {
+	if _, err := PalettePBToPalette(&PalettePB{Main: "red"}); err == nil {
+		t.Fatal("invalid text must not be converted")
+	}
+	if _, err := PalettePBToPalette(&PalettePB{Raw: []byte("blue")}); err == nil {
+		t.Fatal("invalid text bytes must not be converted")
+	}
+}
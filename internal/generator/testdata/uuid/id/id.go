@@ -0,0 +1,28 @@
+// Package id минимальная реализация UUID для тестов
+package id
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID идентификатор
+type UUID [16]byte
+
+// String представление UUID в виде строки из 32 шестнадцатеричных цифр
+func (u UUID) String() string {
+	return hex.EncodeToString(u[:])
+}
+
+// Parse разбор UUID из строки
+func Parse(s string) (UUID, error) {
+	var u UUID
+	if hex.DecodedLen(len(s)) != len(u) {
+		return u, fmt.Errorf("invalid uuid length %d", len(s))
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, err
+	}
+
+	return u, nil
+}
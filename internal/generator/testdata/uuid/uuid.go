@@ -0,0 +1,16 @@
+// Package uuid структуры для тестов конвертации UUID в строки
+package uuid
+
+import "awesome-converter/internal/generator/testdata/uuid/id"
+
+// Account primary-структура
+type Account struct {
+	ID    id.UUID
+	Owner *id.UUID
+}
+
+// AccountPB secondary-структура
+type AccountPB struct {
+	ID    string
+	Owner string
+}
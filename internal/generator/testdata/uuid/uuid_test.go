@@ -0,0 +1,52 @@
+package uuid
+
+import (
+	"testing"
+
+	"awesome-converter/internal/generator/testdata/uuid/id"
+)
+
+func TestAccountConversions(t *testing.T) {
+	account := id.UUID{1, 2, 3}
+	owner := id.UUID{4, 5, 6}
+
+	pb, err := AccountToAccountPB(&Account{ID: account, Owner: &owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.ID != account.String() || pb.Owner != owner.String() {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := AccountPBToAccount(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.ID != account || back.Owner == nil || *back.Owner != owner {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestAccountZeroValues(t *testing.T) {
+	pb, err := AccountToAccountPB(&Account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.ID != "" || pb.Owner != "" {
+		t.Fatalf("zero uuid must be converted into empty string, got %v", pb)
+	}
+
+	back, err := AccountPBToAccount(&AccountPB{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.ID != (id.UUID{}) || back.Owner != nil {
+		t.Fatalf("empty string must be converted into zero uuid, got %v", back)
+	}
+}
+
+func TestAccountInvalidUUID(t *testing.T) {
+	if _, err := AccountPBToAccount(&AccountPB{ID: "not-a-uuid"}); err == nil {
+		t.Fatal("invalid uuid must not be converted")
+	}
+}
@@ -0,0 +1,23 @@
+// Package wellknown структуры для тестов конвертации времени и длительностей в well-known типы протобуфа
+package wellknown
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Event primary-структура
+type Event struct {
+	At    time.Time
+	Took  time.Duration
+	Until *time.Time
+}
+
+// EventPB secondary-структура
+type EventPB struct {
+	At    *timestamppb.Timestamp
+	Took  *durationpb.Duration
+	Until *timestamppb.Timestamp
+}
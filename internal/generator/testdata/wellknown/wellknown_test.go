@@ -0,0 +1,53 @@
+package wellknown
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestEventConversions(t *testing.T) {
+	at := time.Date(2021, 10, 1, 12, 30, 0, 0, time.UTC)
+	until := at.Add(time.Hour)
+
+	pb, err := EventToEventPB(&Event{At: at, Took: time.Minute, Until: &until})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pb.At.AsTime().Equal(at) || pb.Took.AsDuration() != time.Minute || !pb.Until.AsTime().Equal(until) {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := EventPBToEvent(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !back.At.Equal(at) || back.Took != time.Minute || back.Until == nil || !back.Until.Equal(until) {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestEventZeroValues(t *testing.T) {
+	pb, err := EventToEventPB(&Event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.At != nil || pb.Until != nil {
+		t.Fatalf("zero time must be converted into nil timestamp, got %v", pb)
+	}
+
+	back, err := EventPBToEvent(&EventPB{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !back.At.IsZero() || back.Took != 0 || back.Until != nil {
+		t.Fatalf("nil values must be converted into zero ones, got %v", back)
+	}
+}
+
+func TestEventInvalidTimestamp(t *testing.T) {
+	if _, err := EventPBToEvent(&EventPB{At: &timestamppb.Timestamp{Nanos: -1}}); err == nil {
+		t.Fatal("invalid timestamp must not be converted")
+	}
+}
@@ -0,0 +1,21 @@
+// Package wrappers структуры для тестов конвертации в обёртки протобуфа
+package wrappers
+
+import "google.golang.org/protobuf/types/known/wrapperspb"
+
+// Level именованный тип с underlying-типом обёртки
+type Level int32
+
+// Profile primary-структура
+type Profile struct {
+	Name  string
+	Count *int64
+	Level Level
+}
+
+// ProfilePB secondary-структура
+type ProfilePB struct {
+	Name  *wrapperspb.StringValue
+	Count *wrapperspb.Int64Value
+	Level *wrapperspb.Int32Value
+}
@@ -0,0 +1,61 @@
+package wrappers
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProfileConversions(t *testing.T) {
+	count := int64(3)
+	pb, err := ProfileToProfilePB(&Profile{Name: "name", Count: &count, Level: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Name.GetValue() != "name" || pb.Count.GetValue() != 3 || pb.Level.GetValue() != 2 {
+		t.Fatalf("unexpected conversion result %v", pb)
+	}
+
+	back, err := ProfilePBToProfile(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Name != "name" || back.Count == nil || *back.Count != 3 || back.Level != 2 {
+		t.Fatalf("unexpected back conversion result %v", back)
+	}
+}
+
+func TestProfileZeroValues(t *testing.T) {
+	var zero int64
+	pb, err := ProfileToProfilePB(&Profile{Count: &zero})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Name == nil || pb.Count == nil || pb.Count.GetValue() != 0 || pb.Level == nil {
+		t.Fatalf("zero values must be kept, got %v", pb)
+	}
+
+	pb, err = ProfileToProfilePB(&Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Count != nil {
+		t.Fatalf("nil pointer must be converted into nil wrapper, got %v", pb.Count)
+	}
+
+	back, err := ProfilePBToProfile(&ProfilePB{Count: wrapperspb.Int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Name != "" || back.Count == nil || *back.Count != 0 || back.Level != 0 {
+		t.Fatalf("unexpected conversion of zero wrappers %v", back)
+	}
+
+	back, err = ProfilePBToProfile(&ProfilePB{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Count != nil {
+		t.Fatalf("nil wrapper must be converted into nil pointer, got %v", *back.Count)
+	}
+}
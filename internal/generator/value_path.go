@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/matiss/v2"
+)
+
+// convgenPath пакет поддержки генерируемого кода
+const convgenPath = "awesome-converter/convgen"
+
+// valuePath путь к конвертируемому значению в исходной структуре вида Items[3].Labels["env"], индексы и ключи
+// которого известны только во время исполнения
+type valuePath struct {
+	// format формат пути для fmt.Sprintf
+	format string
+	// args выражения генерируемого кода с индексами слайсов и ключами словарей пути
+	args []string
+	// back значение конвертируется в направлении secondary → primary
+	back bool
+}
+
+// fieldPath путь к значению поля структуры
+func fieldPath(field string, back bool) valuePath {
+	return valuePath{
+		format: field,
+		back:   back,
+	}
+}
+
+// sliceElem путь к элементу слайса с индексом index
+func (p valuePath) sliceElem(index string) valuePath {
+	return p.item("[%d]", index)
+}
+
+// mapItem путь к ключу либо значению словаря с ключом key
+func (p valuePath) mapItem(key string, keyType types.Type) valuePath {
+	if isString(keyType) {
+		return p.item("[%q]", key)
+	}
+
+	return p.item("[%v]", key)
+}
+
+func (p valuePath) item(format, arg string) valuePath {
+	args := make([]string, 0, len(p.args)+1)
+	args = append(args, p.args...)
+
+	return valuePath{
+		format: p.format + format,
+		args:   append(args, arg),
+		back:   p.back,
+	}
+}
+
+// expr выражение генерируемого кода со значением пути
+func (p valuePath) expr(r *matiss.GoRenderer) string {
+	if len(p.args) == 0 {
+		return strconv.Quote(p.format)
+	}
+
+	r.Imports().Add("fmt").Ref("fmt")
+	return r.S(`$fmt.Sprintf($0, $1)`, strconv.Quote(p.format), strings.Join(p.args, ", "))
+}
+
+// conversionError выражение генерируемого кода с ошибкой конвертации значения value типа srcType в тип dstType по
+// данному пути с причиной cause. С библиотеками ошибок pkg и std сгенерированный код не зависит от convgen: путь
+// добавляется к сообщению обёрткой причины.
+func (g *Generator) conversionError(
+	r *matiss.GoRenderer,
+	whoami valuePath,
+	cause string,
+	value string,
+	srcType types.Type,
+	dstType types.Type,
+) string {
+	switch g.errorsBackend {
+	case ErrorsStd:
+		r.Imports().Add("fmt").Ref("fmt")
+		args := append([]string{strconv.Quote("convert " + whoami.format + ": %w")}, whoami.args...)
+		return r.S(`$fmt.Errorf($0)`, strings.Join(append(args, cause), ", "))
+	case ErrorsPkg:
+		g.importErrors(r)
+		if len(whoami.args) == 0 {
+			return r.S(`$errors.Wrap($0, $1)`, cause, strconv.Quote("convert "+whoami.format))
+		}
+
+		return r.S(
+			`$errors.Wrapf($0, $1, $2)`,
+			cause,
+			strconv.Quote("convert "+whoami.format),
+			strings.Join(whoami.args, ", "),
+		)
+	}
+
+	primType, secType := srcType, dstType
+	if whoami.back {
+		primType, secType = dstType, srcType
+	}
+
+	r.Imports().Add(convgenPath).Ref("convgen")
+	return r.S(
+		`$convgen.Wrap($0, $1, $2, $3, $4)`,
+		cause,
+		whoami.expr(r),
+		strconv.Quote(typeString(primType)),
+		strconv.Quote(typeString(secType)),
+		value,
+	)
+}
+
+// typeString название типа с коротким названием пакета
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return p.Name()
+	})
+}
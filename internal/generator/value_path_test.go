@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func Test_valuePath(t *testing.T) {
+	str := types.Typ[types.String]
+	i64 := types.Typ[types.Int64]
+
+	tests := []struct {
+		name       string
+		path       valuePath
+		wantFormat string
+		wantArgs   []string
+	}{
+		{
+			name:       "field",
+			path:       fieldPath("Items", false),
+			wantFormat: "Items",
+		},
+		{
+			name:       "slice-map-slice",
+			path:       fieldPath("Items", false).sliceElem("i").mapItem("keyval1", str).sliceElem("i2"),
+			wantFormat: "Items[%d][%q][%d]",
+			wantArgs:   []string{"i", "keyval1", "i2"},
+		},
+		{
+			name:       "int-key",
+			path:       fieldPath("Labels", true).mapItem("keyval", i64),
+			wantFormat: "Labels[%v]",
+			wantArgs:   []string{"keyval"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.path.format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", tt.path.format, tt.wantFormat)
+			}
+			if !reflect.DeepEqual(tt.path.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", tt.path.args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 func main() {
 	var cli cliArgs
 	cli.Generate.Primary.needLocal = true
+	cli.Check.Primary.needLocal = true
+	cli.Explain.Primary.needLocal = true
 	parser := kong.Must(
 		&cli,
 		kong.Name(app.Name),
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"awesome-converter/internal/generator"
+	"gopkg.in/yaml.v2"
+
+	"gitlab.stageoffice.ru/UCS-COMMON/errors"
+)
+
+// manifest описание набора конвертаций проекта
+type manifest struct {
+	// Errors библиотека ошибок генерируемого кода для всех конвертаций: ucs, pkg или std
+	Errors      string               `yaml:"errors"`
+	Conversions []manifestConversion `yaml:"conversions"`
+}
+
+// manifestConversion описание конвертаций для пары структур
+type manifestConversion struct {
+	// Primary primary-структура в виде <rel-path>:<name>
+	Primary string `yaml:"primary"`
+	// Secondary secondary-структура в виде <pkg-path>:<name>
+	Secondary string `yaml:"secondary"`
+	// Method название метода конвертации primary → secondary
+	Method string `yaml:"method"`
+	// Map ручное сопоставление полей primary и secondary структур
+	Map map[string]string `yaml:"map"`
+	// Ignore поля исключаемые из конвертации
+	Ignore []string `yaml:"ignore"`
+	// Output имя файла для генерируемого кода в пакете primary-структуры
+	Output string `yaml:"output"`
+	// Strict, StrictTo, StrictFrom запрет ручной конвертации, аналогично опциям команды generate
+	Strict     bool `yaml:"strict"`
+	StrictTo   bool `yaml:"strict_to"`
+	StrictFrom bool `yaml:"strict_from"`
+	// FieldHooks ручная конвертация отдельных полей вместо всей структуры
+	FieldHooks bool `yaml:"field_hooks"`
+	// FromFunc, FromMethod название функции либо метода secondary-структуры для конвертации secondary → primary
+	FromFunc   string `yaml:"from_func"`
+	FromMethod string `yaml:"from_method"`
+	// NoTo, NoFrom отключение генерации конвертаций primary → secondary и secondary → primary
+	NoTo   bool `yaml:"no_to"`
+	NoFrom bool `yaml:"no_from"`
+	// EnumFallback, EnumFallbacks поведение конвертации перечислений для значений не имеющих пары, общее и для
+	// отдельных перечислений-приёмников
+	EnumFallback  string            `yaml:"enum_fallback"`
+	EnumFallbacks map[string]string `yaml:"enum_fallbacks"`
+	// EnumMapTo, EnumMapFrom ручное сопоставление констант перечислений для конвертаций primary → secondary и
+	// secondary → primary
+	EnumMapTo   map[string]string `yaml:"enum_map_to"`
+	EnumMapFrom map[string]string `yaml:"enum_map_from"`
+	// CheckNarrowing проверка сужающих приведений числовых типов
+	CheckNarrowing bool `yaml:"check_narrowing"`
+	// CollectErrors сбор ошибок конвертации всех полей
+	CollectErrors bool `yaml:"collect_errors"`
+	// Errors библиотека ошибок генерируемого кода, если не задана, то используется общая для манифеста
+	Errors string `yaml:"errors"`
+}
+
+// loadManifest чтение манифеста из данного файла
+func loadManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read manifest file")
+	}
+
+	var res manifest
+	if err := yaml.UnmarshalStrict(data, &res); err != nil {
+		return nil, errors.Wrap(err, "parse manifest")
+	}
+
+	if len(res.Conversions) == 0 {
+		return nil, errors.New("no conversions listed in the manifest")
+	}
+
+	return &res, nil
+}
+
+// pairs построение описаний пар структур для генератора
+func (m *manifest) pairs(modPath string) ([]generator.Pair, error) {
+	var res []generator.Pair
+	for i, conv := range m.Conversions {
+		prim := structPath{
+			needLocal: true,
+		}
+		if err := prim.UnmarshalText([]byte(conv.Primary)); err != nil {
+			return nil, errors.Wrapf(err, "parse primary of conversion #%d", i+1)
+		}
+
+		var sec structPath
+		if err := sec.UnmarshalText([]byte(conv.Secondary)); err != nil {
+			return nil, errors.Wrapf(err, "parse secondary of conversion #%d", i+1)
+		}
+
+		opts := []generator.Option{
+			generator.WithManualMatches(conv.Map),
+			generator.WithIgnoredFields(conv.Ignore...),
+			generator.WithStrict(conv.Strict || conv.StrictTo, conv.Strict || conv.StrictFrom),
+		}
+		if conv.Output != "" {
+			if filepath.Base(conv.Output) != conv.Output || !strings.HasSuffix(conv.Output, ".go") {
+				return nil, errors.Newf(
+					"output of conversion #%d must be a name of Go file without directory, got '%s'",
+					i+1,
+					conv.Output,
+				)
+			}
+
+			opts = append(opts, generator.WithOutput(conv.Output))
+		}
+		if conv.FieldHooks {
+			opts = append(opts, generator.WithFieldHooks())
+		}
+		if conv.FromFunc != "" {
+			opts = append(opts, generator.WithFromFunc(conv.FromFunc))
+		}
+		if conv.FromMethod != "" {
+			opts = append(opts, generator.WithFromMethod(conv.FromMethod))
+		}
+		if conv.NoTo {
+			opts = append(opts, generator.WithoutTo())
+		}
+		if conv.NoFrom {
+			opts = append(opts, generator.WithoutFrom())
+		}
+		if conv.CheckNarrowing {
+			opts = append(opts, generator.WithNarrowingChecks())
+		}
+		if conv.CollectErrors {
+			opts = append(opts, generator.WithCollectErrors())
+		}
+		if len(conv.EnumMapTo) > 0 || len(conv.EnumMapFrom) > 0 {
+			opts = append(opts, generator.WithEnumMapping(conv.EnumMapTo, conv.EnumMapFrom))
+		}
+		fallbacks, err := enumFallbackOptions(conv.EnumFallback, conv.EnumFallbacks)
+		if err != nil {
+			return nil, errors.Wrapf(err, "setup enum fallbacks of conversion #%d", i+1)
+		}
+		opts = append(opts, fallbacks...)
+		backend := m.Errors
+		if conv.Errors != "" {
+			backend = conv.Errors
+		}
+		if backend != "" {
+			b, err := generator.ParseErrorsBackend(backend)
+			if err != nil {
+				return nil, errors.Wrapf(err, "setup errors backend of conversion #%d", i+1)
+			}
+			opts = append(opts, generator.WithErrorsBackend(b))
+		}
+
+		res = append(res, generator.Pair{
+			PrimaryPkg:    undottedPrefix(prim.pkgPath, modPath),
+			PrimaryName:   prim.name,
+			SecondaryPkg:  undottedPrefix(sec.pkgPath, modPath),
+			SecondaryName: sec.name,
+			Method:        conv.Method,
+			Options:       opts,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_manifestPairs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".awesome-converter.yaml")
+	data := `
+conversions:
+  - primary: ./internal/domain:Region
+    secondary: example.com/schema/regions:Region
+    method: Proto
+    map:
+      RegionID: Location
+    ignore:
+      - Internal
+    output: region_proto_convgen.go
+    strict_to: true
+  - primary: ./internal/domain:User
+    secondary: ./internal/storage:User
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := m.pairs("example.com/service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pairs) != 2 {
+		t.Fatalf("2 pairs expected, got %d", len(pairs))
+	}
+
+	if pairs[0].PrimaryPkg != "example.com/service/internal/domain" || pairs[0].PrimaryName != "Region" {
+		t.Errorf("unexpected primary %s:%s", pairs[0].PrimaryPkg, pairs[0].PrimaryName)
+	}
+	if pairs[0].SecondaryPkg != "example.com/schema/regions" || pairs[0].SecondaryName != "Region" {
+		t.Errorf("unexpected secondary %s:%s", pairs[0].SecondaryPkg, pairs[0].SecondaryName)
+	}
+	if pairs[0].Method != "Proto" {
+		t.Errorf("unexpected method %s", pairs[0].Method)
+	}
+	conv := m.Conversions[0]
+	if !reflect.DeepEqual(conv.Map, map[string]string{"RegionID": "Location"}) {
+		t.Errorf("unexpected map %v", conv.Map)
+	}
+	if !reflect.DeepEqual(conv.Ignore, []string{"Internal"}) {
+		t.Errorf("unexpected ignored fields %v", conv.Ignore)
+	}
+	if conv.Output != "region_proto_convgen.go" {
+		t.Errorf("unexpected output %s", conv.Output)
+	}
+	if conv.Strict || !conv.StrictTo || conv.StrictFrom {
+		t.Errorf(
+			"only strict_to expected, got strict=%t strict_to=%t strict_from=%t",
+			conv.Strict,
+			conv.StrictTo,
+			conv.StrictFrom,
+		)
+	}
+	if pairs[1].SecondaryPkg != "example.com/service/internal/storage" {
+		t.Errorf("unexpected secondary package %s", pairs[1].SecondaryPkg)
+	}
+}
+
+func Test_manifestPairsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "non-local-primary",
+			data: `
+conversions:
+  - primary: example.com/domain:Region
+    secondary: example.com/schema:Region
+`,
+		},
+		{
+			name: "output-with-directory",
+			data: `
+conversions:
+  - primary: ./domain:Region
+    secondary: example.com/schema:Region
+    output: ../region_convgen.go
+`,
+		},
+		{
+			name: "unknown-errors-backend",
+			data: `
+errors: multierr
+conversions:
+  - primary: ./domain:Region
+    secondary: example.com/schema:Region
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".awesome-converter.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := loadManifest(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := m.pairs("example.com/service"); err == nil {
+				t.Error("error expected")
+			}
+		})
+	}
+}